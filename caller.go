@@ -0,0 +1,79 @@
+package log
+
+import (
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+
+	flam "github.com/happyhippyhippo/flam"
+)
+
+const (
+	callerSkip         = 6
+	maxStacktraceDepth = 64
+)
+
+func captureCaller(
+	ctx flam.Bag,
+	pc uintptr,
+) {
+	if pc == 0 {
+		pcs := [1]uintptr{}
+		if runtime.Callers(callerSkip, pcs[:]) == 0 {
+			return
+		}
+		pc = pcs[0]
+	}
+
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	if frame.File == "" {
+		return
+	}
+
+	if _, ok := ctx[CallerKey]; !ok {
+		ctx[CallerKey] = shortFile(frame.File) + ":" + strconv.Itoa(frame.Line)
+	}
+	if _, ok := ctx[FunctionKey]; !ok && frame.Function != "" {
+		ctx[FunctionKey] = frame.Function
+	}
+}
+
+func captureStacktrace(
+	ctx flam.Bag,
+) {
+	if _, ok := ctx[StacktraceKey]; ok {
+		return
+	}
+
+	pcs := make([]uintptr, maxStacktraceDepth)
+	pcs = pcs[:runtime.Callers(callerSkip, pcs)]
+	if len(pcs) == 0 {
+		return
+	}
+
+	builder := strings.Builder{}
+	frames := runtime.CallersFrames(pcs)
+	for {
+		frame, more := frames.Next()
+		_, _ = builder.WriteString(frame.Function)
+		_, _ = builder.WriteString("\n\t")
+		_, _ = builder.WriteString(frame.File)
+		_ = builder.WriteByte(':')
+		_, _ = builder.WriteString(strconv.Itoa(frame.Line))
+		if !more {
+			break
+		}
+		_ = builder.WriteByte('\n')
+	}
+
+	ctx[StacktraceKey] = builder.String()
+}
+
+func shortFile(
+	file string,
+) string {
+	dir, name := filepath.Split(file)
+
+	return filepath.Join(filepath.Base(dir), name)
+}
@@ -3,19 +3,96 @@ package log
 const (
 	providerId = "flam.log.provider"
 
-	SerializerCreatorGroup   = "flam.log.serializers.creator"
-	SerializerDriverString   = "flam.log.serializers.driver.string"
-	SerializerDriverJson     = "flam.log.serializers.driver.json"
-	StreamCreatorGroup       = "flam.log.streams.creator"
-	StreamDriverConsole      = "flam.log.streams.driver.console"
-	StreamDriverFile         = "flam.log.streams.driver.file"
-	StreamDriverRotatingFile = "flam.log.streams.driver.rotating-file"
-
-	PathDefaultLevel      = "flam.log.defaults.level"
-	PathDefaultSerializer = "flam.log.defaults.serializer"
-	PathDefaultDisk       = "flam.log.defaults.disk"
-	PathBoot              = "flam.log.boot"
-	PathFlusherFrequency  = "flam.log.flusher.frequency"
-	PathSerializers       = "flam.log.serializers"
-	PathStreams           = "flam.log.streams"
+	SerializerCreatorGroup    = "flam.log.serializers.creator"
+	SerializerDriverString    = "flam.log.serializers.driver.string"
+	SerializerDriverJson      = "flam.log.serializers.driver.json"
+	SerializerDriverLogfmt    = "flam.log.serializers.driver.logfmt"
+	SerializerDriverGelf      = "flam.log.serializers.driver.gelf"
+	StreamCreatorGroup        = "flam.log.streams.creator"
+	StreamDriverConsole       = "flam.log.streams.driver.console"
+	StreamDriverFile          = "flam.log.streams.driver.file"
+	StreamDriverRotatingFile  = "flam.log.streams.driver.rotating-file"
+	StreamDriverSyslog        = "flam.log.streams.driver.syslog"
+	StreamDriverSocket        = "flam.log.streams.driver.socket"
+	StreamDriverHttp          = "flam.log.streams.driver.http"
+	StreamDriverLoki          = "flam.log.streams.driver.loki"
+	StreamDriverElasticsearch = "flam.log.streams.driver.elasticsearch"
+	StreamDriverOtlp          = "flam.log.streams.driver.otlp"
+	StreamDriverGelf          = "flam.log.streams.driver.gelf"
+	StreamDriverFluent        = "flam.log.streams.driver.fluent"
+	StreamDriverSlog          = "flam.log.streams.driver.slog"
+	SlogHandlerGroup          = "flam.log.slog.handlers"
+	ContextExtractorGroup     = "flam.log.context.extractor"
+
+	JsonLayoutDefault = "default"
+	JsonLayoutEcs     = "ecs"
+
+	StringContextLogfmt = "logfmt"
+	StringContextJson   = "json"
+
+	RotationHourly  = "hourly"
+	RotationDaily   = "daily"
+	RotationWeekly  = "weekly"
+	RotationMonthly = "monthly"
+
+	OverflowBlock       = "block"
+	OverflowDropNewest  = "drop_newest"
+	OverflowDropOldest  = "drop_oldest"
+	OverflowFlushInline = "flush_inline"
+
+	CompressionGzip = "gzip"
+	CompressionZstd = "zstd"
+	CompressionZlib = "zlib"
+
+	SyslogFormatRFC5424 = "rfc5424"
+	SyslogFormatRFC3164 = "rfc3164"
+
+	HttpFormatNdjson    = "ndjson"
+	HttpFormatJsonArray = "json_array"
+
+	LokiFormatJson     = "json"
+	LokiFormatProtobuf = "protobuf"
+
+	OtlpFormatJson     = "json"
+	OtlpFormatProtobuf = "protobuf"
+
+	OtlpChannelAttribute = "attribute"
+	OtlpChannelScope     = "scope"
+
+	GelfEmptyMessage = "(empty)"
+
+	FluentModeMessage       = "message"
+	FluentModeForward       = "forward"
+	FluentModePackedForward = "packed_forward"
+
+	CallerKey     = "caller"
+	FunctionKey   = "function"
+	StacktraceKey = "stacktrace"
+
+	FlusherModePeriodic = "periodic"
+	FlusherModeSync     = "sync"
+
+	PathDefaultLevel        = "flam.log.defaults.level"
+	PathDefaultSerializer   = "flam.log.defaults.serializer"
+	PathDefaultDisk         = "flam.log.defaults.disk"
+	PathDefaultCaller       = "flam.log.defaults.caller"
+	PathDefaultStacktraceAt = "flam.log.defaults.stacktrace_at"
+	PathBoot                = "flam.log.boot"
+	PathFlusherFrequency    = "flam.log.flusher.frequency"
+	PathFlusherMode         = "flam.log.flusher.mode"
+	PathFlusherFlushOn      = "flam.log.flusher.flush_on"
+	PathBufferMaxEntries    = "flam.log.buffer.max_entries"
+	PathBufferMaxBytes      = "flam.log.buffer.max_bytes"
+	PathBufferOverflow      = "flam.log.buffer.overflow"
+	PathBufferBlockTimeout  = "flam.log.buffer.block_timeout"
+	PathRetryBackoff        = "flam.log.retry.backoff"
+	PathRetryMaxBackoff     = "flam.log.retry.max_backoff"
+	PathRetryMaxPending     = "flam.log.retry.max_pending"
+	PathAsyncEnabled        = "flam.log.async.enabled"
+	PathAsyncCapacity       = "flam.log.async.capacity"
+	PathAsyncDrainTimeout   = "flam.log.async.drain_timeout"
+	PathShutdownTimeout     = "flam.log.shutdown.timeout"
+	PathContextExtractors   = "flam.log.context.extractors"
+	PathSerializers         = "flam.log.serializers"
+	PathStreams             = "flam.log.streams"
 )
@@ -0,0 +1,33 @@
+package log
+
+import (
+	"context"
+
+	flam "github.com/happyhippyhippo/flam"
+)
+
+type ContextExtractor func(ctx context.Context) flam.Bag
+
+type NamedContextExtractor struct {
+	Id        string
+	Extractor ContextExtractor
+}
+
+func extractContext(
+	ctx context.Context,
+	extractors []ContextExtractor,
+	bag []flam.Bag,
+) []flam.Bag {
+	if ctx == nil || len(extractors) == 0 {
+		return bag
+	}
+
+	extracted := make([]flam.Bag, 0, len(extractors)+len(bag))
+	for _, extractor := range extractors {
+		if fields := extractor(ctx); len(fields) != 0 {
+			extracted = append(extracted, fields)
+		}
+	}
+
+	return append(extracted, bag...)
+}
@@ -1,7 +1,32 @@
 package log
 
+import (
+	"time"
+)
+
 var (
-	DefaultLevel      = Info
-	DefaultSerializer = ""
-	DefaultDisk       = ""
+	DefaultLevel                = Info
+	DefaultSerializer           = ""
+	DefaultDisk                 = ""
+	DefaultCaller               = false
+	DefaultStacktraceAt         = None
+	DefaultBufferBlockTimeout   = time.Second
+	DefaultRetryBackoff         = 100 * time.Millisecond
+	DefaultRetryMaxBackoff      = 30 * time.Second
+	DefaultRetryMaxPending      = 10000
+	DefaultAsyncCapacity        = 1024
+	DefaultAsyncDrainTimeout    = 5 * time.Second
+	DefaultShutdownTimeout      = 5 * time.Second
+	DefaultSpillMaxEntries      = 1000
+	DefaultNetTimeout           = 10 * time.Second
+	DefaultHttpBatchSize        = 100
+	DefaultHttpBatchInterval    = time.Second
+	DefaultHttpTimeout          = 10 * time.Second
+	DefaultHttpMaxRetries       = 3
+	DefaultGelfChunkSize        = 1420
+	DefaultFluentTag            = "flam"
+	DefaultFluentBatchSize      = 100
+	DefaultFluentBatchInterval  = time.Second
+	DefaultFluentAckTimeout     = 10 * time.Second
+	DefaultSlogChannelAttribute = "channel"
 )
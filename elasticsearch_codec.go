@@ -0,0 +1,133 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type elasticsearchCodec struct {
+	index string
+	idKey string
+}
+
+func newElasticsearchCodec(
+	index string,
+	idKey string,
+) httpBatchCodec {
+	return &elasticsearchCodec{
+		index: index,
+		idKey: idKey,
+	}
+}
+
+func (codec elasticsearchCodec) Encode(
+	items []httpBatchItem,
+) (string, []byte, error) {
+	body := &bytes.Buffer{}
+	for _, item := range items {
+		action := map[string]string{"_index": codec.indexOf(item.entry.Timestamp)}
+		if id := codec.idOf(item.entry); id != "" {
+			action["_id"] = id
+		}
+
+		line, e := json.Marshal(map[string]any{"index": action})
+		if e != nil {
+			return "", nil, e
+		}
+
+		_, _ = body.Write(line)
+		_ = body.WriteByte('\n')
+		_, _ = body.Write(bytes.TrimRight(item.payload, "\n"))
+		_ = body.WriteByte('\n')
+	}
+
+	return "application/x-ndjson", body.Bytes(), nil
+}
+
+func (elasticsearchCodec) Decode(
+	items []httpBatchItem,
+	status int,
+	body []byte,
+) ([]httpBatchItem, int, error) {
+	if status < http.StatusOK || status >= http.StatusMultipleChoices {
+		return nil, len(items), newErrHttpStatus(status)
+	}
+
+	response := struct {
+		Errors bool `json:"errors"`
+		Items  []map[string]struct {
+			Status int `json:"status"`
+			Error  struct {
+				Type   string `json:"type"`
+				Reason string `json:"reason"`
+			} `json:"error"`
+		} `json:"items"`
+	}{}
+	if e := json.Unmarshal(body, &response); e != nil {
+		return nil, len(items), e
+	}
+
+	if !response.Errors {
+		return nil, 0, nil
+	}
+
+	var retry []httpBatchItem
+	var reasons []string
+	dropped := 0
+	for i, result := range response.Items {
+		if i >= len(items) {
+			break
+		}
+
+		for _, action := range result {
+			switch {
+			case action.Status >= http.StatusOK && action.Status < http.StatusMultipleChoices:
+			case action.Status == http.StatusTooManyRequests || action.Status >= http.StatusInternalServerError:
+				retry = append(retry, items[i])
+				reasons = append(reasons, fmt.Sprintf("%d %s", action.Status, action.Error.Type))
+			default:
+				dropped++
+				reasons = append(reasons, fmt.Sprintf("%d %s: %s", action.Status, action.Error.Type, action.Error.Reason))
+			}
+		}
+	}
+
+	if len(reasons) == 0 {
+		return nil, 0, nil
+	}
+
+	return retry, dropped, newErrBulkItems(strings.Join(reasons, ", "))
+}
+
+func (codec elasticsearchCodec) indexOf(
+	timestamp time.Time,
+) string {
+	timestamp = timestamp.UTC()
+
+	return strings.NewReplacer(
+		"%Y", timestamp.Format("2006"),
+		"%m", timestamp.Format("01"),
+		"%d", timestamp.Format("02"),
+		"%H", timestamp.Format("15"),
+		"%%", "%",
+	).Replace(codec.index)
+}
+
+func (codec elasticsearchCodec) idOf(
+	entry Entry,
+) string {
+	if codec.idKey == "" {
+		return ""
+	}
+
+	value := entry.Ctx.Get(codec.idKey)
+	if value == nil {
+		return ""
+	}
+
+	return logfmtSerializer{}.format(value)
+}
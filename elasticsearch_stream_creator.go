@@ -0,0 +1,63 @@
+package log
+
+import (
+	"sort"
+
+	flam "github.com/happyhippyhippo/flam"
+)
+
+type elasticsearchStreamCreator struct {
+	streamCreator
+}
+
+func newElasticsearchStreamCreator(
+	serializerFactory serializerFactory,
+) StreamCreator {
+	return &elasticsearchStreamCreator{
+		streamCreator: streamCreator{
+			serializerFactory: serializerFactory,
+		},
+	}
+}
+
+func (elasticsearchStreamCreator) Accept(
+	config flam.Bag,
+) bool {
+	return config.String("driver") == StreamDriverElasticsearch &&
+		config.Has("url") &&
+		config.Has("index")
+}
+
+func (creator elasticsearchStreamCreator) Create(
+	config flam.Bag,
+) (Stream, error) {
+	var serializer Serializer
+	var e error
+	if serializerId := config.String("serializer"); serializerId != "" {
+		serializer, e = creator.serializerFactory.Get(serializerId)
+	} else {
+		serializer, e = newJsonSerializer(jsonSerializerOptions{layout: JsonLayoutEcs})
+	}
+	if e != nil {
+		return nil, e
+	}
+
+	writer, e := newHttpBatchWriter(
+		newElasticsearchCodec(
+			config.String("index"),
+			config.String("id_key")),
+		httpBatchWriterOptionsFrom(config))
+	if e != nil {
+		return nil, e
+	}
+
+	channels := creator.getChannels(config.Slice("channels"))
+	sort.Strings(channels)
+
+	return newStream(
+		LevelFrom(config.Get("level"), DefaultLevel),
+		channels,
+		serializer,
+		writer,
+		true), nil
+}
@@ -0,0 +1,101 @@
+package log
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	flam "github.com/happyhippyhippo/flam"
+)
+
+func Test_elasticsearchStream(t *testing.T) {
+	timestamp := time.Date(2021, 1, 2, 3, 4, 5, 6000000, time.UTC)
+
+	t.Run("should write the ecs documents to the dated index", func(t *testing.T) {
+		recorder := &httpRecorder{replies: []string{`{"errors":false,"items":[]}`}}
+		server := httptest.NewServer(recorder)
+		defer server.Close()
+
+		stream, e := getDriverTestStream(t, StreamDriverElasticsearch, flam.Bag{"url": server.URL})
+		require.NoError(t, e)
+
+		require.NoError(t, stream.Broadcast(timestamp, Info, "message1", flam.Bag{"request": flam.Bag{"id": 12}}))
+		require.NoError(t, stream.Broadcast(timestamp, Info, "message2", flam.Bag{}))
+		recorder.Await(t, 1)
+
+		assert.Equal(
+			t,
+			[]string{strings.Join([]string{
+				`{"index":{"_id":"12","_index":"logs-2021.01.02"}}`,
+				`{"@timestamp":"2021-01-02T03:04:05.006Z","log.level":"info","message":"message1","request":{"id":12}}`,
+				`{"index":{"_index":"logs-2021.01.02"}}`,
+				`{"@timestamp":"2021-01-02T03:04:05.006Z","log.level":"info","message":"message2"}`,
+				``,
+			}, "\n")},
+			recorder.Bodies())
+		assert.Equal(t, "application/x-ndjson", recorder.requests[0].Header.Get("Content-Type"))
+	})
+
+	t.Run("should retry only the failed items", func(t *testing.T) {
+		recorder := &httpRecorder{replies: []string{
+			`{"errors":true,"items":[{"index":{"status":201}},{"index":{"status":429,"error":{"type":"es_rejected_execution_exception"}}}]}`,
+			`{"errors":false,"items":[{"index":{"status":201}}]}`,
+		}}
+		server := httptest.NewServer(recorder)
+		defer server.Close()
+
+		stream, e := getDriverTestStream(t, StreamDriverElasticsearch, flam.Bag{"url": server.URL})
+		require.NoError(t, e)
+
+		require.NoError(t, stream.Broadcast(timestamp, Info, "message1", flam.Bag{}))
+		require.NoError(t, stream.Broadcast(timestamp, Info, "message2", flam.Bag{}))
+
+		bodies := recorder.Await(t, 2)
+		require.Len(t, bodies, 2)
+		assert.Contains(t, bodies[0], "message1")
+		assert.NotContains(t, bodies[1], "message1")
+		assert.Contains(t, bodies[1], "message2")
+	})
+
+	t.Run("should report the rejected items without retrying them", func(t *testing.T) {
+		recorder := &httpRecorder{replies: []string{
+			`{"errors":true,"items":[{"index":{"status":201}},{"index":{"status":400,"error":{"type":"mapper_parsing_exception","reason":"failed to parse"}}}]}`,
+		}}
+		server := httptest.NewServer(recorder)
+		defer server.Close()
+
+		stream, e := getDriverTestStream(t, StreamDriverElasticsearch, flam.Bag{"url": server.URL})
+		require.NoError(t, e)
+		reports := reportsOf(stream)
+
+		require.NoError(t, stream.Broadcast(timestamp, Info, "message1", flam.Bag{}))
+		require.NoError(t, stream.Broadcast(timestamp, Info, "message2", flam.Bag{}))
+		report := nextReport(t, reports)
+
+		assert.Equal(t, 1, report.dropped)
+		assert.ErrorIs(t, report.e, ErrBulkItems)
+		assert.ErrorContains(t, report.e, "mapper_parsing_exception")
+		assert.Len(t, recorder.Bodies(), 1)
+	})
+	t.Run("should count every item as dropped on an unreadable bulk response", func(t *testing.T) {
+		recorder := &httpRecorder{replies: []string{`<html>`}}
+		server := httptest.NewServer(recorder)
+		defer server.Close()
+
+		stream, e := getDriverTestStream(t, StreamDriverElasticsearch, flam.Bag{"url": server.URL})
+		require.NoError(t, e)
+		reports := reportsOf(stream)
+
+		require.NoError(t, stream.Broadcast(timestamp, Info, "message1", flam.Bag{}))
+		require.NoError(t, stream.Broadcast(timestamp, Info, "message2", flam.Bag{}))
+		report := nextReport(t, reports)
+
+		assert.Equal(t, 2, report.dropped)
+		assert.Error(t, report.e)
+		assert.Len(t, recorder.Bodies(), 1)
+	})
+}
@@ -0,0 +1,46 @@
+package log
+
+import (
+	"time"
+
+	flam "github.com/happyhippyhippo/flam"
+)
+
+type Entry struct {
+	Timestamp time.Time
+	Level     Level
+	Channel   string
+	Message   string
+	Ctx       flam.Bag
+}
+
+const entryOverhead = 64
+
+func entrySize(
+	entry Entry,
+) int {
+	return entryOverhead + len(entry.Channel) + len(entry.Message) + bagSize(entry.Ctx)
+}
+
+func bagSize(
+	bag flam.Bag,
+) int {
+	size := 0
+	for key, value := range bag {
+		size += len(key)
+		switch v := value.(type) {
+		case string:
+			size += len(v)
+		case []byte:
+			size += len(v)
+		case flam.Bag:
+			size += bagSize(v)
+		case *flam.Bag:
+			size += bagSize(*v)
+		default:
+			size += 16
+		}
+	}
+
+	return size
+}
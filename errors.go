@@ -2,13 +2,44 @@ package log
 
 import (
 	"errors"
+	"strconv"
 
 	flam "github.com/happyhippyhippo/flam"
 )
 
 var (
-	ErrStreamNotFound  = errors.New("log stream not found")
-	ErrDuplicateStream = errors.New("duplicate log stream")
+	ErrStreamNotFound           = errors.New("log stream not found")
+	ErrDuplicateStream          = errors.New("duplicate log stream")
+	ErrUnknownCompression       = errors.New("unknown log compression")
+	ErrUnknownRotationPeriod    = errors.New("unknown log rotation period")
+	ErrInvalidSerializerFormat  = errors.New("invalid log serializer format")
+	ErrUnknownOverflowPolicy    = errors.New("unknown log buffer overflow policy")
+	ErrStreamDelivery           = errors.New("log stream delivery failure")
+	ErrAbandonedEntries         = errors.New("log entries abandoned on shutdown")
+	ErrUnknownFlusherMode       = errors.New("unknown log flusher mode")
+	ErrUnknownSyslogFacility    = errors.New("unknown syslog facility")
+	ErrUnknownSyslogFormat      = errors.New("unknown syslog format")
+	ErrConnectionBackoff        = errors.New("log stream connection in backoff")
+	ErrSpillBufferFull          = errors.New("log stream spill buffer full")
+	ErrUnknownHttpFormat        = errors.New("unknown log http format")
+	ErrHttpStatus               = errors.New("unexpected log http response status")
+	ErrUnknownLokiFormat        = errors.New("unknown loki push format")
+	ErrBulkItems                = errors.New("elasticsearch bulk items failed")
+	ErrUnknownOtlpFormat        = errors.New("unknown otlp export format")
+	ErrUnknownOtlpChannelMode   = errors.New("unknown otlp channel mode")
+	ErrGelfMessageTooLarge      = errors.New("gelf message too large")
+	ErrInvalidMsgpack           = errors.New("invalid msgpack data")
+	ErrUnknownFluentMode        = errors.New("unknown fluent forward mode")
+	ErrFluentHandshake          = errors.New("fluent forward handshake failure")
+	ErrFluentAck                = errors.New("fluent forward chunk not acknowledged")
+	ErrSlogHandlerNotFound      = errors.New("slog handler not found")
+	ErrContextExtractorNotFound = errors.New("log context extractor not found")
+	ErrUnknownStringPlaceholder = errors.New("unknown log string serializer placeholder")
+	ErrUnknownStringContextMode = errors.New("unknown log string serializer context mode")
+	ErrStreamBackoff            = errors.New("log stream delivery in backoff")
+	ErrInvalidTlsConfig         = errors.New("invalid log stream tls configuration")
+	ErrMsgpackTooLarge          = errors.New("msgpack data over the decode limits")
+	ErrPartialWrite             = errors.New("log stream message partially written")
 )
 
 func newErrNilReference(
@@ -34,3 +65,255 @@ func newErrDuplicateStream(
 		ErrDuplicateStream,
 		id)
 }
+
+func newErrUnknownCompression(
+	algorithm string,
+) error {
+	return flam.NewErrorFrom(
+		ErrUnknownCompression,
+		algorithm)
+}
+
+func newErrUnknownRotationPeriod(
+	period string,
+) error {
+	return flam.NewErrorFrom(
+		ErrUnknownRotationPeriod,
+		period)
+}
+
+func newErrInvalidSerializerFormat(
+	format string,
+) error {
+	return flam.NewErrorFrom(
+		ErrInvalidSerializerFormat,
+		format)
+}
+
+func newErrUnknownOverflowPolicy(
+	policy string,
+) error {
+	return flam.NewErrorFrom(
+		ErrUnknownOverflowPolicy,
+		policy)
+}
+
+func newErrUnknownFlusherMode(
+	mode string,
+) error {
+	return flam.NewErrorFrom(
+		ErrUnknownFlusherMode,
+		mode)
+}
+
+func newErrUnknownSyslogFacility(
+	facility string,
+) error {
+	return flam.NewErrorFrom(
+		ErrUnknownSyslogFacility,
+		facility)
+}
+
+func newErrUnknownSyslogFormat(
+	format string,
+) error {
+	return flam.NewErrorFrom(
+		ErrUnknownSyslogFormat,
+		format)
+}
+
+func newErrConnectionBackoff(
+	address string,
+) error {
+	return flam.NewErrorFrom(
+		ErrConnectionBackoff,
+		address)
+}
+
+func newErrSpillBufferFull(
+	address string,
+) error {
+	return flam.NewErrorFrom(
+		ErrSpillBufferFull,
+		address)
+}
+
+func newErrUnknownHttpFormat(
+	format string,
+) error {
+	return flam.NewErrorFrom(
+		ErrUnknownHttpFormat,
+		format)
+}
+
+func newErrHttpStatus(
+	status int,
+) error {
+	return flam.NewErrorFrom(
+		ErrHttpStatus,
+		strconv.Itoa(status))
+}
+
+func newErrUnknownLokiFormat(
+	format string,
+) error {
+	return flam.NewErrorFrom(
+		ErrUnknownLokiFormat,
+		format)
+}
+
+func newErrBulkItems(
+	reasons string,
+) error {
+	return flam.NewErrorFrom(
+		ErrBulkItems,
+		reasons)
+}
+
+func newErrUnknownOtlpFormat(
+	format string,
+) error {
+	return flam.NewErrorFrom(
+		ErrUnknownOtlpFormat,
+		format)
+}
+
+func newErrUnknownOtlpChannelMode(
+	mode string,
+) error {
+	return flam.NewErrorFrom(
+		ErrUnknownOtlpChannelMode,
+		mode)
+}
+
+func newErrGelfMessageTooLarge(
+	size int,
+) error {
+	return flam.NewErrorFrom(
+		ErrGelfMessageTooLarge,
+		strconv.Itoa(size))
+}
+
+func newErrInvalidMsgpack(
+	code byte,
+) error {
+	return flam.NewErrorFrom(
+		ErrInvalidMsgpack,
+		"0x"+strconv.FormatUint(uint64(code), 16))
+}
+
+func newErrUnknownFluentMode(
+	mode string,
+) error {
+	return flam.NewErrorFrom(
+		ErrUnknownFluentMode,
+		mode)
+}
+
+func newErrFluentHandshake(
+	reason string,
+) error {
+	return flam.NewErrorFrom(
+		ErrFluentHandshake,
+		reason)
+}
+
+func newErrFluentAck(
+	chunk string,
+) error {
+	return flam.NewErrorFrom(
+		ErrFluentAck,
+		chunk)
+}
+
+func newErrSlogHandlerNotFound(
+	id string,
+) error {
+	return flam.NewErrorFrom(
+		ErrSlogHandlerNotFound,
+		id)
+}
+
+func newErrContextExtractorNotFound(
+	id string,
+) error {
+	return flam.NewErrorFrom(
+		ErrContextExtractorNotFound,
+		id)
+}
+
+func newErrUnknownStringPlaceholder(
+	placeholder string,
+) error {
+	return flam.NewErrorFrom(
+		ErrUnknownStringPlaceholder,
+		placeholder)
+}
+
+func newErrUnknownStringContextMode(
+	mode string,
+) error {
+	return flam.NewErrorFrom(
+		ErrUnknownStringContextMode,
+		mode)
+}
+
+func newErrStreamDelivery(
+	id string,
+	e error,
+) error {
+	return errors.Join(
+		flam.NewErrorFrom(
+			ErrStreamDelivery,
+			id),
+		e)
+}
+
+func newErrStreamBackoff(
+	pending int,
+	e error,
+) error {
+	return errors.Join(
+		flam.NewErrorFrom(
+			ErrStreamBackoff,
+			strconv.Itoa(pending)),
+		e)
+}
+
+func newErrInvalidTlsConfig(
+	file string,
+	e error,
+) error {
+	return errors.Join(
+		flam.NewErrorFrom(
+			ErrInvalidTlsConfig,
+			file),
+		e)
+}
+
+func newErrMsgpackTooLarge(
+	size int,
+) error {
+	return flam.NewErrorFrom(
+		ErrMsgpackTooLarge,
+		strconv.Itoa(size))
+}
+
+func newErrAbandonedEntries(
+	count uint64,
+) error {
+	return flam.NewErrorFrom(
+		ErrAbandonedEntries,
+		strconv.FormatUint(count, 10))
+}
+
+func newErrPartialWrite(
+	address string,
+	e error,
+) error {
+	return errors.Join(
+		flam.NewErrorFrom(
+			ErrPartialWrite,
+			address),
+		e)
+}
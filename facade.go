@@ -1,10 +1,14 @@
 package log
 
 import (
+	"context"
+	"time"
+
 	flam "github.com/happyhippyhippo/flam"
 )
 
 type Facade interface {
+	IsEnabled(level Level, channel string) bool
 	Signal(level Level, channel, message string, ctx ...flam.Bag) error
 	Broadcast(level Level, message string, ctx ...flam.Bag) error
 	FatalSignal(channel, message string, ctx ...flam.Bag) error
@@ -19,7 +23,23 @@ type Facade interface {
 	InfoBroadcast(message string, ctx ...flam.Bag) error
 	DebugSignal(channel, message string, ctx ...flam.Bag) error
 	DebugBroadcast(message string, ctx ...flam.Bag) error
+	SignalCtx(ctx context.Context, level Level, channel, message string, bag ...flam.Bag) error
+	BroadcastCtx(ctx context.Context, level Level, message string, bag ...flam.Bag) error
+	FatalSignalCtx(ctx context.Context, channel, message string, bag ...flam.Bag) error
+	FatalBroadcastCtx(ctx context.Context, message string, bag ...flam.Bag) error
+	ErrorSignalCtx(ctx context.Context, channel, message string, bag ...flam.Bag) error
+	ErrorBroadcastCtx(ctx context.Context, message string, bag ...flam.Bag) error
+	WarningSignalCtx(ctx context.Context, channel, message string, bag ...flam.Bag) error
+	WarningBroadcastCtx(ctx context.Context, message string, bag ...flam.Bag) error
+	NoticeSignalCtx(ctx context.Context, channel, message string, bag ...flam.Bag) error
+	NoticeBroadcastCtx(ctx context.Context, message string, bag ...flam.Bag) error
+	InfoSignalCtx(ctx context.Context, channel, message string, bag ...flam.Bag) error
+	InfoBroadcastCtx(ctx context.Context, message string, bag ...flam.Bag) error
+	DebugSignalCtx(ctx context.Context, channel, message string, bag ...flam.Bag) error
+	DebugBroadcastCtx(ctx context.Context, message string, bag ...flam.Bag) error
+	Channel(channel string) Logger
 	Flush() error
+	DroppedEntries() uint64
 
 	HasSerializer(id string) bool
 	ListSerializers() []string
@@ -49,6 +69,13 @@ func newFacade(
 	}
 }
 
+func (facade *facade) IsEnabled(
+	level Level,
+	channel string,
+) bool {
+	return facade.manager.IsEnabled(level, channel)
+}
+
 func (facade *facade) Signal(
 	level Level,
 	channel,
@@ -156,10 +183,163 @@ func (facade *facade) DebugBroadcast(
 	return facade.manager.Broadcast(Debug, message, ctx...)
 }
 
+func (facade *facade) SignalCtx(
+	ctx context.Context,
+	level Level,
+	channel,
+	message string,
+	bag ...flam.Bag,
+) error {
+	return facade.manager.SignalCtx(ctx, level, channel, message, bag...)
+}
+
+func (facade *facade) BroadcastCtx(
+	ctx context.Context,
+	level Level,
+	message string,
+	bag ...flam.Bag,
+) error {
+	return facade.manager.BroadcastCtx(ctx, level, message, bag...)
+}
+
+func (facade *facade) FatalSignalCtx(
+	ctx context.Context,
+	channel,
+	message string,
+	bag ...flam.Bag,
+) error {
+	return facade.manager.SignalCtx(ctx, Fatal, channel, message, bag...)
+}
+
+func (facade *facade) FatalBroadcastCtx(
+	ctx context.Context,
+	message string,
+	bag ...flam.Bag,
+) error {
+	return facade.manager.BroadcastCtx(ctx, Fatal, message, bag...)
+}
+
+func (facade *facade) ErrorSignalCtx(
+	ctx context.Context,
+	channel,
+	message string,
+	bag ...flam.Bag,
+) error {
+	return facade.manager.SignalCtx(ctx, Error, channel, message, bag...)
+}
+
+func (facade *facade) ErrorBroadcastCtx(
+	ctx context.Context,
+	message string,
+	bag ...flam.Bag,
+) error {
+	return facade.manager.BroadcastCtx(ctx, Error, message, bag...)
+}
+
+func (facade *facade) WarningSignalCtx(
+	ctx context.Context,
+	channel,
+	message string,
+	bag ...flam.Bag,
+) error {
+	return facade.manager.SignalCtx(ctx, Warning, channel, message, bag...)
+}
+
+func (facade *facade) WarningBroadcastCtx(
+	ctx context.Context,
+	message string,
+	bag ...flam.Bag,
+) error {
+	return facade.manager.BroadcastCtx(ctx, Warning, message, bag...)
+}
+
+func (facade *facade) NoticeSignalCtx(
+	ctx context.Context,
+	channel,
+	message string,
+	bag ...flam.Bag,
+) error {
+	return facade.manager.SignalCtx(ctx, Notice, channel, message, bag...)
+}
+
+func (facade *facade) NoticeBroadcastCtx(
+	ctx context.Context,
+	message string,
+	bag ...flam.Bag,
+) error {
+	return facade.manager.BroadcastCtx(ctx, Notice, message, bag...)
+}
+
+func (facade *facade) InfoSignalCtx(
+	ctx context.Context,
+	channel,
+	message string,
+	bag ...flam.Bag,
+) error {
+	return facade.manager.SignalCtx(ctx, Info, channel, message, bag...)
+}
+
+func (facade *facade) InfoBroadcastCtx(
+	ctx context.Context,
+	message string,
+	bag ...flam.Bag,
+) error {
+	return facade.manager.BroadcastCtx(ctx, Info, message, bag...)
+}
+
+func (facade *facade) DebugSignalCtx(
+	ctx context.Context,
+	channel,
+	message string,
+	bag ...flam.Bag,
+) error {
+	return facade.manager.SignalCtx(ctx, Debug, channel, message, bag...)
+}
+
+func (facade *facade) DebugBroadcastCtx(
+	ctx context.Context,
+	message string,
+	bag ...flam.Bag,
+) error {
+	return facade.manager.BroadcastCtx(ctx, Debug, message, bag...)
+}
+
+func (facade *facade) signalAt(
+	ctx context.Context,
+	timestamp time.Time,
+	pc uintptr,
+	level Level,
+	channel,
+	message string,
+	bag flam.Bag,
+) error {
+	if !facade.manager.IsEnabled(level, channel) {
+		return nil
+	}
+
+	return facade.manager.signal(
+		timestamp,
+		pc,
+		level,
+		channel,
+		message,
+		extractContext(ctx, facade.manager.activeExtractors(), []flam.Bag{bag}))
+}
+
+func (facade *facade) Channel(
+	channel string,
+) Logger {
+	return newLogger(facade.manager, channel)
+}
+
 func (facade *facade) Flush() error {
 	return facade.manager.Flush()
 }
 
+func (facade *facade) DroppedEntries() uint64 {
+	return facade.manager.DroppedEntries()
+}
+
 func (facade *facade) HasSerializer(
 	id string,
 ) bool {
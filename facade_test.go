@@ -1,8 +1,15 @@
 package log
 
 import (
+	"bytes"
+	"context"
 	"errors"
+	"log/slog"
+	"path/filepath"
+	"runtime"
+	"strconv"
 	"testing"
+	gotime "time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
@@ -15,6 +22,92 @@ import (
 	time "github.com/happyhippyhippo/flam-time"
 )
 
+func Test_facade_IsEnabled(t *testing.T) {
+	t.Run("should not be enabled if there are no streams", func(t *testing.T) {
+		container := dig.New()
+		require.NoError(t, time.NewProvider().Register(container))
+		require.NoError(t, filesystem.NewProvider().Register(container))
+		require.NoError(t, config.NewProvider().Register(container))
+		require.NoError(t, NewProvider().Register(container))
+
+		assert.NoError(t, container.Invoke(func(facade Facade) {
+			assert.False(t, facade.IsEnabled(Fatal, "channel"))
+			assert.False(t, facade.IsEnabled(Fatal, ""))
+		}))
+	})
+
+	t.Run("should be enabled for any level if a stream level is unknown", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		container := dig.New()
+		require.NoError(t, time.NewProvider().Register(container))
+		require.NoError(t, filesystem.NewProvider().Register(container))
+		require.NoError(t, config.NewProvider().Register(container))
+		require.NoError(t, NewProvider().Register(container))
+
+		assert.NoError(t, container.Invoke(func(facade Facade) {
+			require.NoError(t, facade.AddStream("stream", NewStreamMock(ctrl)))
+
+			assert.True(t, facade.IsEnabled(Debug, "channel"))
+			assert.True(t, facade.IsEnabled(Debug, ""))
+		}))
+	})
+
+	t.Run("should follow the streams level and channels", func(t *testing.T) {
+		container := dig.New()
+		require.NoError(t, time.NewProvider().Register(container))
+		require.NoError(t, filesystem.NewProvider().Register(container))
+		require.NoError(t, config.NewProvider().Register(container))
+		require.NoError(t, NewProvider().Register(container))
+
+		target := newStream(Warning, []string{"channel_1"}, newDefaultJsonSerializer(t), &bytes.Buffer{}, false)
+
+		assert.NoError(t, container.Invoke(func(facade Facade) {
+			require.NoError(t, facade.AddStream("stream", target))
+
+			assert.True(t, facade.IsEnabled(Error, "channel_1"))
+			assert.True(t, facade.IsEnabled(Warning, "channel_1"))
+			assert.False(t, facade.IsEnabled(Info, "channel_1"))
+			assert.False(t, facade.IsEnabled(Error, "channel_2"))
+			assert.True(t, facade.IsEnabled(Warning, ""))
+			assert.False(t, facade.IsEnabled(Info, ""))
+
+			require.NoError(t, target.SetLevel(Debug))
+			require.NoError(t, target.AddChannel("channel_2"))
+
+			assert.True(t, facade.IsEnabled(Debug, "channel_1"))
+			assert.True(t, facade.IsEnabled(Debug, "channel_2"))
+
+			require.NoError(t, facade.RemoveStream("stream"))
+
+			assert.False(t, facade.IsEnabled(Fatal, "channel_1"))
+		}))
+	})
+
+	t.Run("should drop the entries not accepted by any stream before buffering", func(t *testing.T) {
+		container := dig.New()
+		require.NoError(t, time.NewProvider().Register(container))
+		require.NoError(t, filesystem.NewProvider().Register(container))
+		require.NoError(t, config.NewProvider().Register(container))
+		require.NoError(t, NewProvider().Register(container))
+
+		buffer := &bytes.Buffer{}
+		target := newStream(Warning, []string{"*"}, newDefaultJsonSerializer(t), buffer, false)
+
+		assert.NoError(t, container.Invoke(func(facade Facade) {
+			require.NoError(t, facade.AddStream("stream", target))
+
+			assert.NoError(t, facade.DebugSignal("channel", "debug message"))
+			assert.NoError(t, facade.InfoBroadcast("info message"))
+			require.NoError(t, target.SetLevel(Debug))
+			assert.NoError(t, facade.Flush())
+
+			assert.Empty(t, buffer.String())
+		}))
+	})
+}
+
 func Test_facade_Signal(t *testing.T) {
 	t.Run("should not send message to stream if not flushed", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
@@ -777,12 +870,14 @@ func Test_facade_Flush(t *testing.T) {
 		stream.EXPECT().
 			Signal(gomock.Any(), Notice, "channel", "message3", flam.Bag{"key3": "value3"}).
 			Return(expectedErr)
+		stream.EXPECT().Close().Return(nil)
 
 		assert.NoError(t, container.Invoke(func(facade Facade) {
 			require.NoError(t, facade.AddStream("stream", stream))
 
 			assert.NoError(t, facade.NoticeSignal("channel", "message3", flam.Bag{"key3": "value3"}))
 			assert.ErrorIs(t, facade.Flush(), expectedErr)
+			assert.NoError(t, facade.RemoveStream("stream"))
 		}))
 	})
 
@@ -801,12 +896,14 @@ func Test_facade_Flush(t *testing.T) {
 		stream.EXPECT().
 			Broadcast(gomock.Any(), Notice, "message3", flam.Bag{"key3": "value3"}).
 			Return(expectedErr)
+		stream.EXPECT().Close().Return(nil)
 
 		assert.NoError(t, container.Invoke(func(facade Facade) {
 			require.NoError(t, facade.AddStream("stream", stream))
 
 			assert.NoError(t, facade.NoticeBroadcast("message3", flam.Bag{"key3": "value3"}))
 			assert.ErrorIs(t, facade.Flush(), expectedErr)
+			assert.NoError(t, facade.RemoveStream("stream"))
 		}))
 	})
 
@@ -842,6 +939,180 @@ func Test_facade_Flush(t *testing.T) {
 	})
 }
 
+func Test_facade_DroppedEntries(t *testing.T) {
+	setup := func(t *testing.T, buffer flam.Bag) *dig.Container {
+		return bootTestContainer(t, flam.Bag{"flam.log.buffer": buffer})
+	}
+
+	t.Run("should return an error on unknown overflow policy", func(t *testing.T) {
+		config.Defaults = flam.Bag{}
+		_ = config.Defaults.Set(PathBufferOverflow, "invalid")
+		defer func() { config.Defaults = flam.Bag{} }()
+
+		container := dig.New()
+		require.NoError(t, time.NewProvider().Register(container))
+		require.NoError(t, filesystem.NewProvider().Register(container))
+		require.NoError(t, config.NewProvider().Register(container))
+		require.NoError(t, NewProvider().Register(container))
+
+		require.NoError(t, config.NewProvider().(flam.BootableProvider).Boot(container))
+
+		assert.ErrorIs(
+			t,
+			NewProvider().(flam.BootableProvider).Boot(container),
+			ErrUnknownOverflowPolicy)
+	})
+
+	t.Run("should drop the newest entries and report them on flush", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		container := setup(t, flam.Bag{"max_entries": 2, "overflow": OverflowDropNewest})
+
+		stream := NewStreamMock(ctrl)
+		gomock.InOrder(
+			stream.EXPECT().Signal(gomock.Any(), Info, "channel", "message1", flam.Bag{}).Return(nil),
+			stream.EXPECT().Signal(gomock.Any(), Info, "channel", "message2", flam.Bag{}).Return(nil),
+			stream.EXPECT().Broadcast(gomock.Any(), Warning, "log buffer overflow", flam.Bag{"dropped": uint64(1)}).Return(nil),
+		)
+
+		assert.NoError(t, container.Invoke(func(facade Facade) {
+			require.NoError(t, facade.AddStream("stream", stream))
+
+			assert.NoError(t, facade.InfoSignal("channel", "message1"))
+			assert.NoError(t, facade.InfoSignal("channel", "message2"))
+			assert.NoError(t, facade.InfoSignal("channel", "message3"))
+			assert.Equal(t, uint64(1), facade.DroppedEntries())
+			assert.NoError(t, facade.Flush())
+		}))
+	})
+
+	t.Run("should drop the oldest entries", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		container := setup(t, flam.Bag{"max_entries": 2, "overflow": OverflowDropOldest})
+
+		stream := NewStreamMock(ctrl)
+		gomock.InOrder(
+			stream.EXPECT().Signal(gomock.Any(), Info, "channel", "message2", flam.Bag{}).Return(nil),
+			stream.EXPECT().Signal(gomock.Any(), Info, "channel", "message3", flam.Bag{}).Return(nil),
+			stream.EXPECT().Broadcast(gomock.Any(), Warning, "log buffer overflow", flam.Bag{"dropped": uint64(1)}).Return(nil),
+		)
+
+		assert.NoError(t, container.Invoke(func(facade Facade) {
+			require.NoError(t, facade.AddStream("stream", stream))
+
+			assert.NoError(t, facade.InfoSignal("channel", "message1"))
+			assert.NoError(t, facade.InfoSignal("channel", "message2"))
+			assert.NoError(t, facade.InfoSignal("channel", "message3"))
+			assert.Equal(t, uint64(1), facade.DroppedEntries())
+			assert.NoError(t, facade.Flush())
+		}))
+	})
+
+	t.Run("should limit the buffer by the entries size", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		container := setup(t, flam.Bag{"max_bytes": 100})
+
+		stream := NewStreamMock(ctrl)
+		gomock.InOrder(
+			stream.EXPECT().Signal(gomock.Any(), Info, "channel", "message1", flam.Bag{}).Return(nil),
+			stream.EXPECT().Broadcast(gomock.Any(), Warning, "log buffer overflow", flam.Bag{"dropped": uint64(1)}).Return(nil),
+		)
+
+		assert.NoError(t, container.Invoke(func(facade Facade) {
+			require.NoError(t, facade.AddStream("stream", stream))
+
+			assert.NoError(t, facade.InfoSignal("channel", "message1"))
+			assert.NoError(t, facade.InfoSignal("channel", "message2"))
+			assert.NoError(t, facade.Flush())
+		}))
+	})
+
+	t.Run("should flush the buffer inline when full", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		container := setup(t, flam.Bag{"max_entries": 1, "overflow": OverflowFlushInline})
+
+		stream := NewStreamMock(ctrl)
+		stream.EXPECT().Signal(gomock.Any(), Info, "channel", "message1", flam.Bag{}).Return(nil)
+
+		assert.NoError(t, container.Invoke(func(facade Facade) {
+			require.NoError(t, facade.AddStream("stream", stream))
+
+			assert.NoError(t, facade.InfoSignal("channel", "message1"))
+			assert.NoError(t, facade.InfoSignal("channel", "message2"))
+			assert.Equal(t, uint64(0), facade.DroppedEntries())
+		}))
+	})
+
+	t.Run("should block until the buffer is flushed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		container := setup(t, flam.Bag{"max_entries": 1, "overflow": OverflowBlock})
+
+		stream := NewStreamMock(ctrl)
+		stream.EXPECT().Signal(gomock.Any(), Info, "channel", gomock.Any(), flam.Bag{}).Return(nil).Times(2)
+
+		assert.NoError(t, container.Invoke(func(facade Facade) {
+			require.NoError(t, facade.AddStream("stream", stream))
+
+			assert.NoError(t, facade.InfoSignal("channel", "message1"))
+
+			done := make(chan struct{})
+			go func() {
+				assert.NoError(t, facade.InfoSignal("channel", "message2"))
+				close(done)
+			}()
+
+			select {
+			case <-done:
+				assert.Fail(t, "signal should be blocked")
+			case <-gotime.After(50 * gotime.Millisecond):
+			}
+
+			assert.NoError(t, facade.Flush())
+			<-done
+			assert.NoError(t, facade.Flush())
+			assert.Equal(t, uint64(0), facade.DroppedEntries())
+		}))
+	})
+
+	t.Run("should drop the entry when the block timeout expires", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		container := setup(t, flam.Bag{
+			"max_entries":   1,
+			"overflow":      OverflowBlock,
+			"block_timeout": 20 * gotime.Millisecond,
+		})
+
+		stream := NewStreamMock(ctrl)
+		gomock.InOrder(
+			stream.EXPECT().Signal(gomock.Any(), Info, "channel", "message1", flam.Bag{}).Return(nil),
+			stream.EXPECT().Broadcast(gomock.Any(), Warning, "log buffer overflow", flam.Bag{"dropped": uint64(1)}).Return(nil),
+		)
+
+		assert.NoError(t, container.Invoke(func(facade Facade) {
+			require.NoError(t, facade.AddStream("stream", stream))
+
+			assert.NoError(t, facade.InfoSignal("channel", "message1"))
+
+			start := gotime.Now()
+			assert.NoError(t, facade.InfoSignal("channel", "message2"))
+			assert.GreaterOrEqual(t, gotime.Since(start), 20*gotime.Millisecond)
+			assert.Equal(t, uint64(1), facade.DroppedEntries())
+			assert.NoError(t, facade.Flush())
+		}))
+	})
+}
+
 func Test_facade_HasSerializer(t *testing.T) {
 	t.Run("should return false if serializer does not exist", func(t *testing.T) {
 		container := dig.New()
@@ -1470,3 +1741,725 @@ func Test_Facade_RemoveAllStreams(t *testing.T) {
 		}))
 	})
 }
+
+func Test_facade_FlushIsolation(t *testing.T) {
+	setup := func(t *testing.T, retry flam.Bag) *dig.Container {
+		return bootTestContainer(t, flam.Bag{"flam.log.retry": retry})
+	}
+
+	t.Run("should deliver to the healthy streams when one fails", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		container := setup(t, flam.Bag{"backoff": gotime.Hour})
+
+		expectedErr := errors.New("error")
+		failing := NewStreamMock(ctrl)
+		failing.EXPECT().Signal(gomock.Any(), Info, "channel", "message1", flam.Bag{}).Return(expectedErr).Times(1)
+		healthy := NewStreamMock(ctrl)
+		gomock.InOrder(
+			healthy.EXPECT().Signal(gomock.Any(), Info, "channel", "message1", flam.Bag{}).Return(nil),
+			healthy.EXPECT().Signal(gomock.Any(), Info, "channel", "message2", flam.Bag{}).Return(nil),
+		)
+
+		assert.NoError(t, container.Invoke(func(facade Facade) {
+			require.NoError(t, facade.AddStream("failing", failing))
+			require.NoError(t, facade.AddStream("healthy", healthy))
+
+			assert.NoError(t, facade.InfoSignal("channel", "message1"))
+			e := facade.Flush()
+			assert.ErrorIs(t, e, ErrStreamDelivery)
+			assert.ErrorIs(t, e, expectedErr)
+			assert.ErrorContains(t, e, "failing")
+			assert.NotContains(t, e.Error(), "healthy")
+
+			assert.NoError(t, facade.InfoSignal("channel", "message2"))
+			e = facade.Flush()
+			assert.ErrorIs(t, e, ErrStreamBackoff)
+			assert.ErrorIs(t, e, expectedErr)
+			assert.ErrorContains(t, e, "failing")
+			assert.NotContains(t, e.Error(), "healthy")
+		}))
+	})
+
+	t.Run("should redeliver only the failed entries after the backoff", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		container := setup(t, flam.Bag{"backoff": 0})
+
+		expectedErr := errors.New("error")
+		stream := NewStreamMock(ctrl)
+		gomock.InOrder(
+			stream.EXPECT().Signal(gomock.Any(), Info, "channel", "message1", flam.Bag{}).Return(nil),
+			stream.EXPECT().Signal(gomock.Any(), Info, "channel", "message2", flam.Bag{}).Return(expectedErr),
+			stream.EXPECT().Signal(gomock.Any(), Info, "channel", "message2", flam.Bag{}).Return(nil),
+			stream.EXPECT().Signal(gomock.Any(), Info, "channel", "message3", flam.Bag{}).Return(nil),
+		)
+
+		assert.NoError(t, container.Invoke(func(facade Facade) {
+			require.NoError(t, facade.AddStream("stream", stream))
+
+			assert.NoError(t, facade.InfoSignal("channel", "message1"))
+			assert.NoError(t, facade.InfoSignal("channel", "message2"))
+			assert.ErrorIs(t, facade.Flush(), expectedErr)
+
+			assert.NoError(t, facade.InfoSignal("channel", "message3"))
+			assert.NoError(t, facade.Flush())
+		}))
+	})
+
+	t.Run("should retry the pending entries without a new flush", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		container := setup(t, flam.Bag{"backoff": 10 * gotime.Millisecond})
+
+		delivered := make(chan struct{})
+		stream := NewStreamMock(ctrl)
+		gomock.InOrder(
+			stream.EXPECT().Signal(gomock.Any(), Info, "channel", "message", flam.Bag{}).Return(errors.New("error")),
+			stream.EXPECT().Signal(gomock.Any(), Info, "channel", "message", flam.Bag{}).DoAndReturn(
+				func(gotime.Time, Level, string, string, flam.Bag) error {
+					close(delivered)
+					return nil
+				}),
+		)
+
+		assert.NoError(t, container.Invoke(func(facade Facade) {
+			require.NoError(t, facade.AddStream("stream", stream))
+
+			assert.NoError(t, facade.InfoSignal("channel", "message"))
+			assert.Error(t, facade.Flush())
+
+			select {
+			case <-delivered:
+			case <-gotime.After(gotime.Second):
+				assert.Fail(t, "pending entry was not retried")
+			}
+		}))
+	})
+
+	t.Run("should drop the oldest pending entries over the limit", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		container := setup(t, flam.Bag{"backoff": gotime.Hour, "max_pending": 1})
+
+		expectedErr := errors.New("error")
+		stream := NewStreamMock(ctrl)
+		stream.EXPECT().Signal(gomock.Any(), Info, "channel", "message1", flam.Bag{}).Return(expectedErr).Times(1)
+
+		assert.NoError(t, container.Invoke(func(facade Facade) {
+			require.NoError(t, facade.AddStream("stream", stream))
+
+			assert.NoError(t, facade.InfoSignal("channel", "message1"))
+			assert.NoError(t, facade.InfoSignal("channel", "message2"))
+			assert.ErrorIs(t, facade.Flush(), expectedErr)
+			assert.Equal(t, uint64(1), facade.DroppedEntries())
+		}))
+	})
+}
+
+func Test_facade_AsyncDelivery(t *testing.T) {
+	setup := func(t *testing.T, async flam.Bag) *dig.Container {
+		async["enabled"] = true
+
+		return bootTestContainer(t, flam.Bag{"flam.log.async": async, PathRetryBackoff: gotime.Hour})
+	}
+
+	t.Run("should not stall the other streams on a slow stream", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		container := setup(t, flam.Bag{})
+
+		release := make(chan struct{})
+		delivered := make(chan struct{})
+		slow := NewStreamMock(ctrl)
+		slow.EXPECT().Signal(gomock.Any(), Info, "channel", "message", flam.Bag{}).DoAndReturn(
+			func(gotime.Time, Level, string, string, flam.Bag) error {
+				<-release
+				return nil
+			}).Times(1)
+		slow.EXPECT().Close().Return(nil).Times(1)
+		fast := NewStreamMock(ctrl)
+		fast.EXPECT().Signal(gomock.Any(), Info, "channel", "message", flam.Bag{}).DoAndReturn(
+			func(gotime.Time, Level, string, string, flam.Bag) error {
+				close(delivered)
+				return nil
+			}).Times(1)
+		fast.EXPECT().Close().Return(nil).Times(1)
+
+		assert.NoError(t, container.Invoke(func(facade Facade) {
+			require.NoError(t, facade.AddStream("fast", fast))
+			require.NoError(t, facade.AddStream("slow", slow))
+
+			assert.NoError(t, facade.InfoSignal("channel", "message"))
+			assert.NoError(t, facade.Flush())
+			<-delivered
+			assert.NoError(t, facade.InfoSignal("channel", "message"))
+
+			close(release)
+			assert.NoError(t, facade.RemoveAllStreams())
+		}))
+	})
+
+	t.Run("should report the worker errors on the next flush", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		container := setup(t, flam.Bag{})
+
+		expectedErr := errors.New("error")
+		delivered := make(chan struct{})
+		stream := NewStreamMock(ctrl)
+		stream.EXPECT().Signal(gomock.Any(), Info, "channel", "message", flam.Bag{}).DoAndReturn(
+			func(gotime.Time, Level, string, string, flam.Bag) error {
+				defer close(delivered)
+				return expectedErr
+			}).Times(1)
+		stream.EXPECT().Close().Return(nil).Times(1)
+
+		assert.NoError(t, container.Invoke(func(facade Facade) {
+			require.NoError(t, facade.AddStream("stream", stream))
+
+			assert.NoError(t, facade.InfoSignal("channel", "message"))
+			assert.NoError(t, facade.Flush())
+			<-delivered
+
+			require.Eventually(t, func() bool {
+				e := facade.Flush()
+				return errors.Is(e, ErrStreamDelivery) && errors.Is(e, expectedErr)
+			}, gotime.Second, gotime.Millisecond)
+			assert.NoError(t, facade.RemoveStream("stream"))
+			assert.Equal(t, uint64(1), facade.DroppedEntries())
+		}))
+	})
+
+	t.Run("should drop the entries over the queue capacity", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		container := setup(t, flam.Bag{"capacity": 1})
+
+		release := make(chan struct{})
+		started := make(chan struct{})
+		stream := NewStreamMock(ctrl)
+		gomock.InOrder(
+			stream.EXPECT().Signal(gomock.Any(), Info, "channel", "message1", flam.Bag{}).DoAndReturn(
+				func(gotime.Time, Level, string, string, flam.Bag) error {
+					close(started)
+					<-release
+					return nil
+				}),
+			stream.EXPECT().Signal(gomock.Any(), Info, "channel", "message2", flam.Bag{}).Return(nil),
+		)
+		stream.EXPECT().Close().Return(nil).Times(1)
+
+		assert.NoError(t, container.Invoke(func(facade Facade) {
+			require.NoError(t, facade.AddStream("stream", stream))
+
+			assert.NoError(t, facade.InfoSignal("channel", "message1"))
+			assert.NoError(t, facade.Flush())
+			<-started
+
+			assert.NoError(t, facade.InfoSignal("channel", "message2"))
+			assert.NoError(t, facade.InfoSignal("channel", "message3"))
+			assert.NoError(t, facade.Flush())
+			assert.Equal(t, uint64(1), facade.DroppedEntries())
+
+			close(release)
+			assert.NoError(t, facade.RemoveStream("stream"))
+		}))
+	})
+
+	t.Run("should retry the pending entries without a new entry", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		container := bootTestContainer(t, flam.Bag{PathAsyncEnabled: true, PathRetryBackoff: 10 * gotime.Millisecond})
+
+		delivered := make(chan struct{})
+		stream := NewStreamMock(ctrl)
+		gomock.InOrder(
+			stream.EXPECT().Signal(gomock.Any(), Info, "channel", "message", flam.Bag{}).Return(errors.New("error")),
+			stream.EXPECT().Signal(gomock.Any(), Info, "channel", "message", flam.Bag{}).DoAndReturn(
+				func(gotime.Time, Level, string, string, flam.Bag) error {
+					close(delivered)
+					return nil
+				}),
+		)
+		stream.EXPECT().Close().Return(nil).Times(1)
+
+		assert.NoError(t, container.Invoke(func(facade Facade) {
+			require.NoError(t, facade.AddStream("stream", stream))
+
+			assert.NoError(t, facade.InfoSignal("channel", "message"))
+			assert.NoError(t, facade.Flush())
+
+			select {
+			case <-delivered:
+			case <-gotime.After(gotime.Second):
+				assert.Fail(t, "pending entry was not retried")
+			}
+			assert.NoError(t, facade.RemoveStream("stream"))
+		}))
+	})
+
+	t.Run("should not hold the manager while draining a removed stream", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		container := setup(t, flam.Bag{"drain_timeout": gotime.Second})
+
+		release := make(chan struct{})
+		started := make(chan struct{})
+		slow := NewStreamMock(ctrl)
+		slow.EXPECT().Signal(gomock.Any(), Info, "channel", "message", flam.Bag{}).DoAndReturn(
+			func(gotime.Time, Level, string, string, flam.Bag) error {
+				close(started)
+				<-release
+				return nil
+			}).Times(1)
+		slow.EXPECT().Close().Return(nil).Times(1)
+
+		assert.NoError(t, container.Invoke(func(facade Facade) {
+			require.NoError(t, facade.AddStream("slow", slow))
+
+			assert.NoError(t, facade.InfoSignal("channel", "message"))
+			assert.NoError(t, facade.Flush())
+			<-started
+
+			removed := make(chan struct{})
+			go func() {
+				assert.NoError(t, facade.RemoveStream("slow"))
+				close(removed)
+			}()
+
+			require.Eventually(t, func() bool {
+				return !facade.HasStream("slow")
+			}, gotime.Second, gotime.Millisecond)
+			assert.NoError(t, facade.Flush())
+
+			close(release)
+			<-removed
+		}))
+	})
+
+	t.Run("should abandon the queued entries after the drain timeout", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		container := setup(t, flam.Bag{"drain_timeout": 10})
+
+		release := make(chan struct{})
+		closed := make(chan struct{})
+		started := make(chan struct{})
+		stream := NewStreamMock(ctrl)
+		stream.EXPECT().Signal(gomock.Any(), Info, "channel", "message1", flam.Bag{}).DoAndReturn(
+			func(gotime.Time, Level, string, string, flam.Bag) error {
+				close(started)
+				<-release
+				return nil
+			}).Times(1)
+		stream.EXPECT().Close().DoAndReturn(func() error {
+			close(closed)
+			return nil
+		}).Times(1)
+
+		assert.NoError(t, container.Invoke(func(facade Facade) {
+			require.NoError(t, facade.AddStream("stream", stream))
+
+			assert.NoError(t, facade.InfoSignal("channel", "message1"))
+			assert.NoError(t, facade.InfoSignal("channel", "message2"))
+			assert.NoError(t, facade.Flush())
+			<-started
+
+			assert.NoError(t, facade.RemoveStream("stream"))
+			assert.Equal(t, uint64(1), facade.DroppedEntries())
+
+			select {
+			case <-closed:
+				assert.Fail(t, "stream closed while the worker was delivering")
+			case <-gotime.After(20 * gotime.Millisecond):
+			}
+
+			close(release)
+			<-closed
+		}))
+	})
+}
+
+func Test_facade_FlushMode(t *testing.T) {
+	setup := func(t *testing.T, flusher flam.Bag) *dig.Container {
+		return bootTestContainer(t, flam.Bag{"flam.log.flusher": flusher})
+	}
+
+	t.Run("should return an error on unknown flusher mode", func(t *testing.T) {
+		config.Defaults = flam.Bag{}
+		_ = config.Defaults.Set(PathFlusherMode, "invalid")
+		defer func() { config.Defaults = flam.Bag{} }()
+
+		container := dig.New()
+		require.NoError(t, time.NewProvider().Register(container))
+		require.NoError(t, filesystem.NewProvider().Register(container))
+		require.NoError(t, config.NewProvider().Register(container))
+		require.NoError(t, NewProvider().Register(container))
+
+		require.NoError(t, config.NewProvider().(flam.BootableProvider).Boot(container))
+
+		assert.ErrorIs(
+			t,
+			NewProvider().(flam.BootableProvider).Boot(container),
+			ErrUnknownFlusherMode)
+	})
+
+	t.Run("should write the entries inside the signal on sync mode", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		container := setup(t, flam.Bag{"mode": FlusherModeSync})
+
+		expectedErr := errors.New("error")
+		stream := NewStreamMock(ctrl)
+		gomock.InOrder(
+			stream.EXPECT().Signal(gomock.Any(), Info, "channel", "message", flam.Bag{}).Return(nil),
+			stream.EXPECT().Broadcast(gomock.Any(), Debug, "message", flam.Bag{}).Return(expectedErr),
+			stream.EXPECT().Close().Return(nil),
+		)
+
+		assert.NoError(t, container.Invoke(func(facade Facade) {
+			require.NoError(t, facade.AddStream("stream", stream))
+
+			assert.NoError(t, facade.InfoSignal("channel", "message"))
+			assert.ErrorIs(t, facade.DebugBroadcast("message"), expectedErr)
+			assert.NoError(t, facade.RemoveStream("stream"))
+		}))
+	})
+
+	t.Run("should flush the buffered entries on the flush_on level", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		container := setup(t, flam.Bag{"flush_on": "error"})
+
+		delivered := false
+		stream := NewStreamMock(ctrl)
+		gomock.InOrder(
+			stream.EXPECT().Signal(gomock.Any(), Info, "channel", "message1", flam.Bag{}).Return(nil),
+			stream.EXPECT().Signal(gomock.Any(), Error, "channel", "message2", flam.Bag{}).Return(nil),
+			stream.EXPECT().Signal(gomock.Any(), Warning, "channel", "message3", flam.Bag{}).DoAndReturn(
+				func(gotime.Time, Level, string, string, flam.Bag) error {
+					delivered = true
+					return nil
+				}),
+			stream.EXPECT().Signal(gomock.Any(), Fatal, "channel", "message4", flam.Bag{}).Return(nil),
+		)
+
+		assert.NoError(t, container.Invoke(func(facade Facade) {
+			require.NoError(t, facade.AddStream("stream", stream))
+
+			assert.NoError(t, facade.InfoSignal("channel", "message1"))
+			assert.NoError(t, facade.ErrorSignal("channel", "message2"))
+			assert.NoError(t, facade.WarningSignal("channel", "message3"))
+			assert.False(t, delivered)
+
+			assert.NoError(t, facade.FatalSignal("channel", "message4"))
+			assert.True(t, delivered)
+		}))
+	})
+}
+
+func Test_facade_ContextExtractors(t *testing.T) {
+	type ctxKey string
+
+	extractors := []NamedContextExtractor{
+		{Id: "trace", Extractor: func(ctx context.Context) flam.Bag {
+			if trace, ok := ctx.Value(ctxKey("trace")).(string); ok {
+				return flam.Bag{"trace_id": trace}
+			}
+			return nil
+		}},
+		{Id: "tenant", Extractor: func(ctx context.Context) flam.Bag {
+			if tenant, ok := ctx.Value(ctxKey("tenant")).(string); ok {
+				return flam.Bag{"tenant": tenant}
+			}
+			return nil
+		}},
+	}
+
+	setup := func(t *testing.T, active []any) *dig.Container {
+		var provide []func(container *dig.Container) error
+		for _, extractor := range extractors {
+			provide = append(provide, func(container *dig.Container) error {
+				return container.Provide(func() NamedContextExtractor {
+					return extractor
+				}, dig.Group(ContextExtractorGroup))
+			})
+		}
+
+		return newTestContainer(t, flam.Bag{PathContextExtractors: active}, provide...)
+	}
+
+	ctx := context.WithValue(context.WithValue(context.Background(), ctxKey("trace"), "abc"), ctxKey("tenant"), "acme")
+
+	t.Run("should return an error on unknown extractor", func(t *testing.T) {
+		container := setup(t, []any{"invalid"})
+
+		assert.ErrorIs(
+			t,
+			NewProvider().(flam.BootableProvider).Boot(container),
+			ErrContextExtractorNotFound)
+	})
+
+	t.Run("should ignore the context when no extractor is active", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		container := setup(t, nil)
+		require.NoError(t, NewProvider().(flam.BootableProvider).Boot(container))
+
+		stream := NewStreamMock(ctrl)
+		stream.EXPECT().Signal(gomock.Any(), Info, "channel", "message", flam.Bag{"field": "value"}).Return(nil).Times(1)
+
+		assert.NoError(t, container.Invoke(func(facade Facade) {
+			require.NoError(t, facade.AddStream("stream", stream))
+
+			assert.NoError(t, facade.InfoSignalCtx(ctx, "channel", "message", flam.Bag{"field": "value"}))
+			assert.NoError(t, facade.Flush())
+		}))
+	})
+
+	t.Run("should merge the fields of the active extractors before the call site context", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		container := setup(t, []any{"trace", "tenant"})
+		require.NoError(t, NewProvider().(flam.BootableProvider).Boot(container))
+
+		stream := NewStreamMock(ctrl)
+		gomock.InOrder(
+			stream.EXPECT().Signal(gomock.Any(), Error, "channel", "message", flam.Bag{
+				"trace_id": "abc",
+				"tenant":   "override",
+			}).Return(nil),
+			stream.EXPECT().Broadcast(gomock.Any(), Warning, "message", flam.Bag{}).Return(nil),
+		)
+
+		assert.NoError(t, container.Invoke(func(facade Facade) {
+			require.NoError(t, facade.AddStream("stream", stream))
+
+			assert.NoError(t, facade.ErrorSignalCtx(ctx, "channel", "message", flam.Bag{"tenant": "override"}))
+			assert.NoError(t, facade.WarningBroadcastCtx(context.Background(), "message"))
+			assert.NoError(t, facade.Flush())
+		}))
+	})
+
+	t.Run("should route every level helper", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		container := setup(t, []any{"trace"})
+		require.NoError(t, NewProvider().(flam.BootableProvider).Boot(container))
+
+		expected := flam.Bag{"trace_id": "abc"}
+		stream := NewStreamMock(ctrl)
+		for _, level := range []Level{Fatal, Error, Warning, Notice, Info, Debug} {
+			stream.EXPECT().Signal(gomock.Any(), level, "channel", "message", expected).Return(nil).Times(2)
+			stream.EXPECT().Broadcast(gomock.Any(), level, "message", expected).Return(nil).Times(2)
+		}
+
+		assert.NoError(t, container.Invoke(func(facade Facade) {
+			require.NoError(t, facade.AddStream("stream", stream))
+
+			for _, level := range []Level{Fatal, Error, Warning, Notice, Info, Debug} {
+				assert.NoError(t, facade.SignalCtx(ctx, level, "channel", "message"))
+				assert.NoError(t, facade.BroadcastCtx(ctx, level, "message"))
+			}
+			assert.NoError(t, facade.FatalSignalCtx(ctx, "channel", "message"))
+			assert.NoError(t, facade.FatalBroadcastCtx(ctx, "message"))
+			assert.NoError(t, facade.ErrorSignalCtx(ctx, "channel", "message"))
+			assert.NoError(t, facade.ErrorBroadcastCtx(ctx, "message"))
+			assert.NoError(t, facade.WarningSignalCtx(ctx, "channel", "message"))
+			assert.NoError(t, facade.WarningBroadcastCtx(ctx, "message"))
+			assert.NoError(t, facade.NoticeSignalCtx(ctx, "channel", "message"))
+			assert.NoError(t, facade.NoticeBroadcastCtx(ctx, "message"))
+			assert.NoError(t, facade.InfoSignalCtx(ctx, "channel", "message"))
+			assert.NoError(t, facade.InfoBroadcastCtx(ctx, "message"))
+			assert.NoError(t, facade.DebugSignalCtx(ctx, "channel", "message"))
+			assert.NoError(t, facade.DebugBroadcastCtx(ctx, "message"))
+			assert.NoError(t, facade.Flush())
+		}))
+	})
+
+	t.Run("should extract the context on the logger and slog calls", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		container := setup(t, []any{"trace"})
+		require.NoError(t, NewProvider().(flam.BootableProvider).Boot(container))
+
+		stream := NewStreamMock(ctrl)
+		for _, level := range []Level{Fatal, Error, Warning, Notice, Info, Debug} {
+			stream.EXPECT().Signal(gomock.Any(), level, "channel", "message", flam.Bag{
+				"trace_id": "abc",
+				"id":       1,
+			}).Return(nil).Times(2)
+		}
+		stream.EXPECT().Signal(gomock.Any(), Info, "channel", "message", flam.Bag{
+			"trace_id": "abc",
+			"id":       int64(1),
+		}).Return(nil).Times(1)
+		stream.EXPECT().Broadcast(gomock.Any(), Warning, "message", flam.Bag{
+			"trace_id": "abc",
+		}).Return(nil).Times(1)
+
+		assert.NoError(t, container.Invoke(func(facade Facade) {
+			require.NoError(t, facade.AddStream("stream", stream))
+			logger := facade.Channel("channel").With(flam.Bag{"id": 1})
+			slogger := slog.New(NewSlogHandler(facade, "channel"))
+
+			for _, level := range []Level{Fatal, Error, Warning, Notice, Info, Debug} {
+				assert.NoError(t, logger.SignalCtx(ctx, level, "message"))
+			}
+			assert.NoError(t, logger.FatalCtx(ctx, "message"))
+			assert.NoError(t, logger.ErrorCtx(ctx, "message"))
+			assert.NoError(t, logger.WarningCtx(ctx, "message"))
+			assert.NoError(t, logger.NoticeCtx(ctx, "message"))
+			assert.NoError(t, logger.InfoCtx(ctx, "message"))
+			assert.NoError(t, logger.DebugCtx(ctx, "message"))
+			slogger.InfoContext(ctx, "message", "channel", "channel", "id", 1)
+			slogger.WarnContext(ctx, "message")
+			assert.NoError(t, facade.Flush())
+		}))
+	})
+}
+
+func Test_facade_CallerCapture(t *testing.T) {
+	setup := func(t *testing.T, defaults flam.Bag) *dig.Container {
+		return bootTestContainer(t, flam.Bag{"flam.log.defaults": defaults})
+	}
+
+	capture := func(ctrl *gomock.Controller, captured *[]flam.Bag) Stream {
+		stream := NewStreamMock(ctrl)
+		stream.EXPECT().Signal(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ gotime.Time, _ Level, _, _ string, ctx flam.Bag) error {
+				*captured = append(*captured, ctx)
+				return nil
+			}).AnyTimes()
+		stream.EXPECT().Broadcast(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ gotime.Time, _ Level, _ string, ctx flam.Bag) error {
+				*captured = append(*captured, ctx)
+				return nil
+			}).AnyTimes()
+
+		return stream
+	}
+
+	line := func() string {
+		_, file, line, _ := runtime.Caller(1)
+		return filepath.Base(filepath.Dir(file)) + "/" + filepath.Base(file) + ":" + strconv.Itoa(line+1)
+	}
+
+	t.Run("should not capture the caller by default", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		container := setup(t, flam.Bag{})
+
+		var captured []flam.Bag
+		assert.NoError(t, container.Invoke(func(facade Facade) {
+			require.NoError(t, facade.AddStream("stream", capture(ctrl, &captured)))
+
+			assert.NoError(t, facade.ErrorSignal("channel", "message"))
+			assert.NoError(t, facade.Flush())
+		}))
+
+		assert.Equal(t, []flam.Bag{{}}, captured)
+	})
+
+	t.Run("should capture the call site through every entry point", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		container := setup(t, flam.Bag{"caller": true})
+
+		var expected []string
+		var captured []flam.Bag
+		assert.NoError(t, container.Invoke(func(facade Facade) {
+			require.NoError(t, facade.AddStream("stream", capture(ctrl, &captured)))
+			logger := facade.Channel("channel").With(flam.Bag{"id": 1})
+			slogger := slog.New(NewSlogHandler(facade, "channel"))
+
+			expected = append(expected, line())
+			assert.NoError(t, facade.Signal(Info, "channel", "message"))
+			expected = append(expected, line())
+			assert.NoError(t, facade.InfoBroadcast("message"))
+			expected = append(expected, line())
+			assert.NoError(t, facade.ErrorSignalCtx(context.Background(), "channel", "message"))
+			expected = append(expected, line())
+			assert.NoError(t, logger.Info("message"))
+			expected = append(expected, line())
+			assert.NoError(t, logger.InfoCtx(context.Background(), "message"))
+			expected = append(expected, line())
+			assert.NoError(t, logger.SignalCtx(context.Background(), Info, "message"))
+			expected = append(expected, line())
+			slogger.Info("message")
+			expected = append(expected, line())
+			slogger.InfoContext(context.Background(), "message")
+			assert.NoError(t, facade.Flush())
+		}))
+
+		require.Len(t, captured, len(expected))
+		for i, ctx := range captured {
+			assert.Equal(t, expected[i], ctx[CallerKey])
+			assert.Contains(t, ctx[FunctionKey], "Test_facade_CallerCapture")
+		}
+	})
+
+	t.Run("should not override the caller fields given on the call site", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		container := setup(t, flam.Bag{"caller": true})
+
+		var captured []flam.Bag
+		assert.NoError(t, container.Invoke(func(facade Facade) {
+			require.NoError(t, facade.AddStream("stream", capture(ctrl, &captured)))
+
+			assert.NoError(t, facade.InfoSignal("channel", "message", flam.Bag{CallerKey: "preset"}))
+			assert.NoError(t, facade.Flush())
+		}))
+
+		require.Len(t, captured, 1)
+		assert.Equal(t, "preset", captured[0][CallerKey])
+		assert.Contains(t, captured[0][FunctionKey], "Test_facade_CallerCapture")
+	})
+
+	t.Run("should attach the stacktrace from the stacktrace_at level", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		container := setup(t, flam.Bag{"stacktrace_at": "error"})
+
+		var captured []flam.Bag
+		assert.NoError(t, container.Invoke(func(facade Facade) {
+			require.NoError(t, facade.AddStream("stream", capture(ctrl, &captured)))
+
+			assert.NoError(t, facade.WarningSignal("channel", "message"))
+			assert.NoError(t, facade.ErrorSignal("channel", "message"))
+			assert.NoError(t, facade.FatalBroadcast("message"))
+			assert.NoError(t, facade.Flush())
+		}))
+
+		require.Len(t, captured, 3)
+		assert.NotContains(t, captured[0], StacktraceKey)
+		for _, ctx := range captured[1:] {
+			stacktrace, ok := ctx[StacktraceKey].(string)
+			require.True(t, ok)
+			assert.Regexp(t, `^github\.com/happyhippyhippo/flam-log\.Test_facade_CallerCapture\.func\S+\n\t\S+/facade_test\.go:\d+\n`, stacktrace)
+			assert.NotContains(t, ctx, CallerKey)
+		}
+	})
+}
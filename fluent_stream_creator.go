@@ -0,0 +1,104 @@
+package log
+
+import (
+	"os"
+	"sort"
+
+	flam "github.com/happyhippyhippo/flam"
+	filesystem "github.com/happyhippyhippo/flam-filesystem"
+)
+
+type fluentStreamCreator struct {
+	streamCreator
+
+	fileSystemFacade filesystem.Facade
+}
+
+func newFluentStreamCreator(
+	fileSystemFacade filesystem.Facade,
+	serializerFactory serializerFactory,
+) StreamCreator {
+	return &fluentStreamCreator{
+		streamCreator: streamCreator{
+			serializerFactory: serializerFactory,
+		},
+		fileSystemFacade: fileSystemFacade,
+	}
+}
+
+func (fluentStreamCreator) Accept(
+	config flam.Bag,
+) bool {
+	return config.String("driver") == StreamDriverFluent &&
+		config.Has("address")
+}
+
+func (creator fluentStreamCreator) Create(
+	config flam.Bag,
+) (Stream, error) {
+	var serializer Serializer
+	if serializerId := config.String("serializer"); serializerId != "" {
+		var e error
+		if serializer, e = creator.serializerFactory.Get(serializerId); e != nil {
+			return nil, e
+		}
+	}
+
+	var security *fluentSecurity
+	if securityConfig := config.Bag("security"); securityConfig.Has("shared_key") {
+		security = &fluentSecurity{
+			sharedKey: securityConfig.String("shared_key"),
+			hostname:  securityConfig.String("self_hostname"),
+			username:  securityConfig.String("username"),
+			password:  securityConfig.String("password"),
+		}
+		if security.hostname == "" {
+			security.hostname, _ = os.Hostname()
+		}
+	}
+
+	tlsConfig, e := tlsConfigFrom(config.Bag("tls"), creator.fileSystemFacade)
+	if e != nil {
+		return nil, e
+	}
+
+	reconnect := config.Bag("reconnect", flam.Bag{})
+
+	writer, e := newFluentWriter(
+		netConnectionOptions{
+			network: config.String("network", "tcp"),
+			address: config.String("address"),
+			timeout: config.Duration("timeout", DefaultNetTimeout),
+			tls:     tlsConfig,
+			retry: retryPolicy{
+				backoff:    reconnect.Duration("backoff", DefaultRetryBackoff),
+				maxBackoff: reconnect.Duration("max_backoff", DefaultRetryMaxBackoff),
+			},
+		},
+		fluentWriterOptions{
+			mode:       config.String("mode"),
+			tagPrefix:  config.String("tag_prefix"),
+			tag:        config.String("tag", DefaultFluentTag),
+			compress:   config.String("compress"),
+			raw:        serializer != nil,
+			batchSize:  config.Int("batch_size", DefaultFluentBatchSize),
+			interval:   config.Duration("batch_interval", DefaultFluentBatchInterval),
+			requireAck: config.Bool("require_ack"),
+			ackTimeout: config.Duration("ack_timeout", DefaultFluentAckTimeout),
+			maxPending: reconnect.Int("max_pending", DefaultRetryMaxPending),
+			security:   security,
+		})
+	if e != nil {
+		return nil, e
+	}
+
+	channels := creator.getChannels(config.Slice("channels"))
+	sort.Strings(channels)
+
+	return newStream(
+		LevelFrom(config.Get("level"), DefaultLevel),
+		channels,
+		serializer,
+		writer,
+		true), nil
+}
@@ -0,0 +1,425 @@
+package log
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	flam "github.com/happyhippyhippo/flam"
+)
+
+func Test_fluentStream(t *testing.T) {
+	listen := func(t *testing.T, handler func(conn net.Conn, messages chan<- []any)) (string, <-chan []any) {
+		listener, e := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, e)
+		t.Cleanup(func() { _ = listener.Close() })
+
+		messages := make(chan []any, 10)
+		go func() {
+			conn, e := listener.Accept()
+			if e != nil {
+				return
+			}
+			defer func() { _ = conn.Close() }()
+
+			handler(conn, messages)
+		}()
+
+		return listener.Addr().String(), messages
+	}
+
+	receive := func(conn net.Conn, messages chan<- []any) {
+		for {
+			message, e := msgpackDecode(conn)
+			if e != nil {
+				return
+			}
+			messages <- message.([]any)
+		}
+	}
+
+	next := func(t *testing.T, messages <-chan []any) []any {
+		select {
+		case message := <-messages:
+			return message
+		case <-time.After(time.Second):
+			require.Fail(t, "message not received")
+			return nil
+		}
+	}
+
+	timestamp := time.Date(2021, 1, 2, 3, 4, 5, 6000000, time.UTC)
+
+	t.Run("should return an error on unknown mode", func(t *testing.T) {
+		_, e := getDriverTestStream(t, StreamDriverFluent, flam.Bag{"address": "127.0.0.1:24224", "mode": "invalid"})
+
+		assert.ErrorIs(t, e, ErrUnknownFluentMode)
+	})
+
+	t.Run("should return an error on compression outside the packed forward mode", func(t *testing.T) {
+		_, e := getDriverTestStream(t, StreamDriverFluent, flam.Bag{"address": "127.0.0.1:24224", "compress": CompressionGzip})
+
+		assert.ErrorIs(t, e, ErrUnknownCompression)
+	})
+
+	t.Run("should send each entry in message mode with the channel as tag", func(t *testing.T) {
+		address, messages := listen(t, receive)
+
+		stream, e := getDriverTestStream(t, StreamDriverFluent, flam.Bag{
+			"address":    address,
+			"mode":       FluentModeMessage,
+			"tag_prefix": "app",
+		})
+		require.NoError(t, e)
+		defer func() { _ = stream.Close() }()
+
+		require.NoError(t, stream.Signal(timestamp, Error, "http", "message", flam.Bag{
+			"request": flam.Bag{"id": 12},
+		}))
+
+		assert.Equal(t, []any{
+			"app.http",
+			timestamp,
+			map[string]any{
+				"channel": "http",
+				"level":   "error",
+				"message": "message",
+				"request": map[string]any{"id": int64(12)},
+			},
+		}, next(t, messages))
+	})
+
+	t.Run("should batch the entries in forward mode", func(t *testing.T) {
+		address, messages := listen(t, receive)
+
+		stream, e := getDriverTestStream(t, StreamDriverFluent, flam.Bag{
+			"address":    address,
+			"batch_size": 2,
+			"serializer": "logfmt",
+		})
+		require.NoError(t, e)
+		defer func() { _ = stream.Close() }()
+
+		require.NoError(t, stream.Broadcast(timestamp, Info, "first", flam.Bag{}))
+		require.NoError(t, stream.Broadcast(timestamp, Info, "second", flam.Bag{}))
+
+		assert.Equal(t, []any{
+			DefaultFluentTag,
+			[]any{
+				[]any{timestamp, map[string]any{"log": "time=2021-01-02T03:04:05.006+0000 level=info msg=first"}},
+				[]any{timestamp, map[string]any{"log": "time=2021-01-02T03:04:05.006+0000 level=info msg=second"}},
+			},
+			map[string]any{"size": int64(2)},
+		}, next(t, messages))
+	})
+
+	t.Run("should rename the context keys colliding with the record fields", func(t *testing.T) {
+		address, messages := listen(t, receive)
+
+		stream, e := getDriverTestStream(t, StreamDriverFluent, flam.Bag{
+			"address": address,
+			"mode":    FluentModeMessage,
+		})
+		require.NoError(t, e)
+		defer func() { _ = stream.Close() }()
+
+		require.NoError(t, stream.Signal(timestamp, Info, "http", "message", flam.Bag{
+			"level":     "custom",
+			"message":   "custom",
+			"ctx.level": "taken",
+		}))
+
+		assert.Equal(t, map[string]any{
+			"channel":       "http",
+			"level":         "info",
+			"message":       "message",
+			"ctx.message":   "custom",
+			"ctx.level":     "taken",
+			"ctx.ctx.level": "custom",
+		}, next(t, messages)[2])
+	})
+
+	t.Run("should keep the batch when the flush fails", func(t *testing.T) {
+		listener, e := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, e)
+		t.Cleanup(func() { _ = listener.Close() })
+
+		messages := make(chan []any, 10)
+		go func() {
+			conn, e := listener.Accept()
+			if e != nil {
+				return
+			}
+			if _, e := msgpackDecode(conn); e == nil {
+				_, _ = conn.Write(msgpackBuffer{}.Value(map[string]any{"ack": "invalid"}))
+			}
+			_ = conn.Close()
+
+			if conn, e = listener.Accept(); e != nil {
+				return
+			}
+			defer func() { _ = conn.Close() }()
+			for {
+				message, e := msgpackDecode(conn)
+				if e != nil {
+					return
+				}
+				messages <- message.([]any)
+
+				options := message.([]any)[2].(map[string]any)
+				_, _ = conn.Write(msgpackBuffer{}.Value(map[string]any{"ack": options["chunk"]}))
+			}
+		}()
+
+		stream, e := getDriverTestStream(t, StreamDriverFluent, flam.Bag{
+			"address":     listener.Addr().String(),
+			"batch_size":  1,
+			"require_ack": true,
+			"reconnect":   flam.Bag{"backoff": time.Millisecond},
+		})
+		require.NoError(t, e)
+		defer func() { _ = stream.Close() }()
+
+		assert.ErrorIs(t, stream.Broadcast(timestamp, Info, "first", flam.Bag{}), ErrFluentAck)
+		time.Sleep(5 * time.Millisecond)
+		require.NoError(t, stream.Broadcast(timestamp, Info, "second", flam.Bag{}))
+
+		events := next(t, messages)[1].([]any)
+		require.Len(t, events, 2)
+		assert.Equal(t, "first", events[0].([]any)[1].(map[string]any)["message"])
+		assert.Equal(t, "second", events[1].([]any)[1].(map[string]any)["message"])
+	})
+
+	t.Run("should drop the oldest entries over the pending limit", func(t *testing.T) {
+		listener, e := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, e)
+		address := listener.Addr().String()
+		require.NoError(t, listener.Close())
+
+		stream, e := getDriverTestStream(t, StreamDriverFluent, flam.Bag{
+			"address":    address,
+			"batch_size": 1,
+			"reconnect":  flam.Bag{"max_pending": 1},
+		})
+		require.NoError(t, e)
+		reports := reportsOf(stream)
+
+		assert.Error(t, stream.Broadcast(timestamp, Info, "first", flam.Bag{}))
+		assert.Error(t, stream.Broadcast(timestamp, Info, "second", flam.Bag{}))
+		assert.Equal(t, testReport{dropped: 1}, nextReport(t, reports))
+
+		assert.Error(t, stream.Close())
+		assert.Equal(t, testReport{dropped: 1}, nextReport(t, reports))
+	})
+
+	t.Run("should report the background flush errors and close once", func(t *testing.T) {
+		listener, e := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, e)
+		address := listener.Addr().String()
+		require.NoError(t, listener.Close())
+
+		stream, e := getDriverTestStream(t, StreamDriverFluent, flam.Bag{
+			"address":        address,
+			"batch_interval": 50 * time.Millisecond,
+		})
+		require.NoError(t, e)
+		reports := reportsOf(stream)
+
+		require.NoError(t, stream.Broadcast(timestamp, Info, "message", flam.Bag{}))
+		assert.Error(t, nextReport(t, reports).e)
+
+		e = stream.Close()
+		assert.Error(t, e)
+		assert.Equal(t, e, stream.Close())
+	})
+
+	t.Run("should send a compressed event stream in packed forward mode", func(t *testing.T) {
+		address, messages := listen(t, receive)
+
+		stream, e := getDriverTestStream(t, StreamDriverFluent, flam.Bag{
+			"address":  address,
+			"mode":     FluentModePackedForward,
+			"compress": CompressionGzip,
+		})
+		require.NoError(t, e)
+
+		require.NoError(t, stream.Signal(timestamp, Info, "http", "message", flam.Bag{}))
+		require.NoError(t, stream.Close())
+
+		message := next(t, messages)
+		require.Len(t, message, 3)
+		assert.Equal(t, "http", message[0])
+		assert.Equal(t, map[string]any{"size": int64(1), "compressed": CompressionGzip}, message[2])
+
+		reader, e := gzip.NewReader(bytes.NewReader(message[1].([]byte)))
+		require.NoError(t, e)
+		events, e := io.ReadAll(reader)
+		require.NoError(t, e)
+
+		event, e := msgpackDecode(bytes.NewReader(events))
+		require.NoError(t, e)
+		assert.Equal(t, []any{
+			timestamp,
+			map[string]any{"channel": "http", "level": "info", "message": "message"},
+		}, event)
+	})
+
+	t.Run("should wait for the chunk acknowledgement", func(t *testing.T) {
+		address, messages := listen(t, func(conn net.Conn, messages chan<- []any) {
+			for {
+				message, e := msgpackDecode(conn)
+				if e != nil {
+					return
+				}
+				messages <- message.([]any)
+
+				options := message.([]any)[3].(map[string]any)
+				_, _ = conn.Write(msgpackBuffer{}.Value(map[string]any{"ack": options["chunk"]}))
+			}
+		})
+
+		stream, e := getDriverTestStream(t, StreamDriverFluent, flam.Bag{
+			"address":     address,
+			"mode":        FluentModeMessage,
+			"require_ack": true,
+		})
+		require.NoError(t, e)
+		defer func() { _ = stream.Close() }()
+
+		require.NoError(t, stream.Broadcast(timestamp, Info, "message", flam.Bag{}))
+
+		message := next(t, messages)
+		assert.NotEmpty(t, message[3].(map[string]any)["chunk"])
+	})
+
+	t.Run("should return an error on an unexpected acknowledgement", func(t *testing.T) {
+		address, _ := listen(t, func(conn net.Conn, _ chan<- []any) {
+			if _, e := msgpackDecode(conn); e == nil {
+				_, _ = conn.Write(msgpackBuffer{}.Value(map[string]any{"ack": "invalid"}))
+			}
+		})
+
+		stream, e := getDriverTestStream(t, StreamDriverFluent, flam.Bag{
+			"address":     address,
+			"mode":        FluentModeMessage,
+			"require_ack": true,
+		})
+		require.NoError(t, e)
+		defer func() { _ = stream.Close() }()
+
+		e = stream.Broadcast(timestamp, Info, "message", flam.Bag{})
+
+		assert.ErrorIs(t, e, ErrFluentAck)
+	})
+
+	t.Run("should return an error on an acknowledgement over the msgpack limits", func(t *testing.T) {
+		address, _ := listen(t, func(conn net.Conn, _ chan<- []any) {
+			if _, e := msgpackDecode(conn); e == nil {
+				_, _ = conn.Write([]byte{0xdb, 0x7f, 0xff, 0xff, 0xff})
+			}
+		})
+
+		stream, e := getDriverTestStream(t, StreamDriverFluent, flam.Bag{
+			"address":     address,
+			"mode":        FluentModeMessage,
+			"require_ack": true,
+		})
+		require.NoError(t, e)
+		defer func() { _ = stream.Close() }()
+
+		e = stream.Broadcast(timestamp, Info, "message", flam.Bag{})
+
+		assert.ErrorIs(t, e, ErrMsgpackTooLarge)
+	})
+
+	t.Run("should return an error on a msgpack value nested over the depth limit", func(t *testing.T) {
+		_, e := msgpackDecode(bytes.NewReader(bytes.Repeat([]byte{0x91}, msgpackMaxDepth+1)))
+
+		assert.ErrorIs(t, e, ErrMsgpackTooLarge)
+	})
+
+	shakeHands := func(sharedKey string) func(conn net.Conn, messages chan<- []any) {
+		return func(conn net.Conn, messages chan<- []any) {
+			nonce := []byte("nonce")
+			_, _ = conn.Write(msgpackBuffer{}.Array(2).String("HELO").Value(map[string]any{
+				"nonce":     nonce,
+				"auth":      []byte("auth"),
+				"keepalive": true,
+			}))
+
+			response, e := msgpackDecode(conn)
+			if e != nil {
+				return
+			}
+			ping := response.([]any)
+			messages <- ping
+
+			salt := []byte(ping[2].(string))
+			if ping[3] != fluentDigest(salt, []byte(ping[1].(string)), nonce, []byte(sharedKey)) {
+				_, _ = conn.Write(msgpackBuffer{}.Value([]any{"PONG", false, "shared key mismatch", "", ""}))
+				return
+			}
+
+			_, _ = conn.Write(msgpackBuffer{}.Value([]any{
+				"PONG",
+				true,
+				"",
+				"server",
+				fluentDigest(salt, []byte("server"), nonce, []byte(sharedKey)),
+			}))
+
+			receive(conn, messages)
+		}
+	}
+
+	t.Run("should authenticate with the shared key before sending", func(t *testing.T) {
+		address, messages := listen(t, shakeHands("secret"))
+
+		stream, e := getDriverTestStream(t, StreamDriverFluent, flam.Bag{
+			"address": address,
+			"mode":    FluentModeMessage,
+			"security": flam.Bag{
+				"shared_key":    "secret",
+				"self_hostname": "client",
+				"username":      "user",
+				"password":      "pass",
+			},
+		})
+		require.NoError(t, e)
+		defer func() { _ = stream.Close() }()
+
+		require.NoError(t, stream.Broadcast(timestamp, Info, "message", flam.Bag{}))
+
+		ping := next(t, messages)
+		assert.Equal(t, "PING", ping[0])
+		assert.Equal(t, "client", ping[1])
+		assert.Equal(t, "user", ping[4])
+		assert.Equal(t, fluentDigest([]byte("auth"), []byte("user"), []byte("pass")), ping[5])
+
+		assert.Equal(t, DefaultFluentTag, next(t, messages)[0])
+	})
+
+	t.Run("should return an error when the handshake is rejected", func(t *testing.T) {
+		address, _ := listen(t, shakeHands("secret"))
+
+		stream, e := getDriverTestStream(t, StreamDriverFluent, flam.Bag{
+			"address": address,
+			"mode":    FluentModeMessage,
+			"security": flam.Bag{
+				"shared_key": "invalid",
+			},
+		})
+		require.NoError(t, e)
+		defer func() { _ = stream.Close() }()
+
+		e = stream.Broadcast(timestamp, Info, "message", flam.Bag{})
+
+		assert.ErrorIs(t, e, ErrFluentHandshake)
+	})
+}
@@ -0,0 +1,523 @@
+package log
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/rand"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"net"
+	"slices"
+	"sync"
+	"time"
+)
+
+type fluentItem struct {
+	tag     string
+	entry   Entry
+	payload []byte
+}
+
+type fluentSecurity struct {
+	sharedKey string
+	hostname  string
+	username  string
+	password  string
+}
+
+type fluentWriterOptions struct {
+	mode       string
+	tagPrefix  string
+	tag        string
+	compress   string
+	raw        bool
+	batchSize  int
+	interval   time.Duration
+	requireAck bool
+	ackTimeout time.Duration
+	maxPending int
+	security   *fluentSecurity
+}
+
+type fluentWriter struct {
+	lock       sync.Locker
+	sendLock   sync.Locker
+	connection *netConnection
+	options    fluentWriterOptions
+	batch      []fluentItem
+	dropped    int
+	reporter   func(dropped int, e error)
+	stop       chan struct{}
+	done       chan struct{}
+	closing    sync.Once
+	closeErr   error
+}
+
+func newFluentWriter(
+	connectionOptions netConnectionOptions,
+	options fluentWriterOptions,
+) (*fluentWriter, error) {
+	switch options.mode {
+	case "":
+		options.mode = FluentModeForward
+	case FluentModeMessage, FluentModeForward, FluentModePackedForward:
+	default:
+		return nil, newErrUnknownFluentMode(options.mode)
+	}
+
+	switch options.compress {
+	case "":
+	case CompressionGzip:
+		if options.mode != FluentModePackedForward {
+			return nil, newErrUnknownCompression(options.compress)
+		}
+	default:
+		return nil, newErrUnknownCompression(options.compress)
+	}
+
+	if options.tag == "" {
+		options.tag = DefaultFluentTag
+	}
+
+	writer := &fluentWriter{
+		lock:     &sync.Mutex{},
+		sendLock: &sync.Mutex{},
+		options:  options,
+	}
+
+	if options.security != nil {
+		connectionOptions.handshake = writer.handshake
+	}
+	writer.connection = newNetConnection(connectionOptions)
+
+	if options.mode != FluentModeMessage && options.interval > 0 {
+		writer.stop = make(chan struct{})
+		writer.done = make(chan struct{})
+		go writer.run()
+	}
+
+	return writer, nil
+}
+
+func (writer *fluentWriter) Write(
+	output []byte,
+) (int, error) {
+	e := writer.writeEntry(Entry{
+		Timestamp: time.Now(),
+		Level:     Info,
+		Message:   string(output),
+	}, output)
+	if e != nil {
+		return 0, e
+	}
+
+	return len(output), nil
+}
+
+func (writer *fluentWriter) Close() error {
+	writer.closing.Do(func() {
+		if writer.stop != nil {
+			close(writer.stop)
+			<-writer.done
+		}
+
+		e := writer.Flush()
+
+		writer.sendLock.Lock()
+		if ce := writer.connection.Close(); e == nil {
+			e = ce
+		}
+		writer.sendLock.Unlock()
+
+		writer.lock.Lock()
+		writer.dropped += len(writer.batch)
+		writer.batch = nil
+		writer.lock.Unlock()
+		writer.notify(nil)
+
+		writer.closeErr = e
+	})
+
+	return writer.closeErr
+}
+
+func (writer *fluentWriter) Flush() error {
+	writer.lock.Lock()
+	items := writer.batch
+	writer.batch = nil
+	writer.lock.Unlock()
+
+	if len(items) == 0 {
+		return nil
+	}
+
+	failed, e := writer.send(items)
+	if len(failed) != 0 {
+		writer.requeue(failed)
+	}
+
+	return e
+}
+
+func (writer *fluentWriter) report(
+	reporter func(dropped int, e error),
+) {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+
+	writer.reporter = reporter
+}
+
+func (writer *fluentWriter) writeEntry(
+	entry Entry,
+	payload []byte,
+) error {
+	item := fluentItem{
+		tag:     writer.tagOf(entry.Channel),
+		entry:   entry,
+		payload: append([]byte(nil), payload...),
+	}
+
+	if writer.options.mode == FluentModeMessage {
+		_, e := writer.send([]fluentItem{item})
+		return e
+	}
+
+	writer.lock.Lock()
+	writer.batch = append(writer.batch, item)
+	full := writer.options.batchSize <= 0 || len(writer.batch) >= writer.options.batchSize
+	writer.lock.Unlock()
+
+	if !full {
+		return nil
+	}
+
+	return writer.Flush()
+}
+
+func (writer *fluentWriter) run() {
+	defer close(writer.done)
+
+	ticker := time.NewTicker(writer.options.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-writer.stop:
+			return
+		case <-ticker.C:
+			if e := writer.Flush(); e != nil {
+				writer.notify(e)
+			}
+		}
+	}
+}
+
+func (writer *fluentWriter) tagOf(
+	channel string,
+) string {
+	if channel == "" {
+		channel = writer.options.tag
+	}
+	if writer.options.tagPrefix == "" {
+		return channel
+	}
+
+	return writer.options.tagPrefix + "." + channel
+}
+
+func (writer *fluentWriter) requeue(
+	items []fluentItem,
+) {
+	writer.lock.Lock()
+	writer.batch = append(items, writer.batch...)
+	if limit := writer.options.maxPending; limit > 0 && len(writer.batch) > limit {
+		over := len(writer.batch) - limit
+		writer.dropped += over
+		writer.batch = writer.batch[over:]
+	}
+	writer.lock.Unlock()
+
+	writer.notify(nil)
+}
+
+func (writer *fluentWriter) notify(
+	e error,
+) {
+	writer.lock.Lock()
+	dropped := writer.dropped
+	reporter := writer.reporter
+	if reporter != nil {
+		writer.dropped = 0
+	}
+	writer.lock.Unlock()
+
+	if reporter != nil && (dropped != 0 || e != nil) {
+		reporter(dropped, e)
+	}
+}
+
+func (writer *fluentWriter) send(
+	items []fluentItem,
+) ([]fluentItem, error) {
+	writer.sendLock.Lock()
+	defer writer.sendLock.Unlock()
+
+	groups := map[string][]fluentItem{}
+	for _, item := range items {
+		groups[item.tag] = append(groups[item.tag], item)
+	}
+
+	tags := make([]string, 0, len(groups))
+	for tag := range groups {
+		tags = append(tags, tag)
+	}
+	slices.Sort(tags)
+
+	for i, tag := range tags {
+		if writer.options.mode == FluentModeMessage {
+			for _, item := range groups[tag] {
+				if e := writer.sendMessage(tag, []fluentItem{item}); e != nil {
+					return nil, e
+				}
+			}
+			continue
+		}
+
+		if e := writer.sendMessage(tag, groups[tag]); e != nil {
+			var failed []fluentItem
+			for _, tag := range tags[i:] {
+				failed = append(failed, groups[tag]...)
+			}
+			return failed, e
+		}
+	}
+
+	return nil, nil
+}
+
+func (writer *fluentWriter) sendMessage(
+	tag string,
+	items []fluentItem,
+) error {
+	chunk := ""
+	if writer.options.requireAck {
+		chunk = base64.StdEncoding.EncodeToString(fluentRandom(16))
+	}
+
+	message, e := writer.encode(tag, items, chunk)
+	if e != nil {
+		return e
+	}
+
+	if _, e := writer.connection.Write(message); e != nil {
+		_ = writer.connection.Close()
+		return e
+	}
+
+	if chunk == "" {
+		return nil
+	}
+
+	if e := writer.awaitAck(chunk); e != nil {
+		_ = writer.connection.Close()
+		return e
+	}
+
+	return nil
+}
+
+func (writer *fluentWriter) encode(
+	tag string,
+	items []fluentItem,
+	chunk string,
+) (msgpackBuffer, error) {
+	options := map[string]any{}
+	if chunk != "" {
+		options["chunk"] = chunk
+	}
+
+	var message msgpackBuffer
+	switch writer.options.mode {
+	case FluentModeMessage:
+		message = message.Array(3 + min(len(options), 1)).
+			String(tag).
+			EventTime(items[0].entry.Timestamp).
+			Value(writer.record(items[0]))
+		if len(options) != 0 {
+			message = message.Value(options)
+		}
+
+		return message, nil
+	case FluentModeForward:
+		message = message.Array(3).String(tag).Array(len(items))
+		for _, item := range items {
+			message = message.Array(2).EventTime(item.entry.Timestamp).Value(writer.record(item))
+		}
+	default:
+		var events msgpackBuffer
+		for _, item := range items {
+			events = events.Array(2).EventTime(item.entry.Timestamp).Value(writer.record(item))
+		}
+
+		if writer.options.compress == CompressionGzip {
+			compressed := &bytes.Buffer{}
+			encoder := gzip.NewWriter(compressed)
+			_, _ = encoder.Write(events)
+			if e := encoder.Close(); e != nil {
+				return nil, e
+			}
+			events = compressed.Bytes()
+			options["compressed"] = CompressionGzip
+		}
+
+		message = message.Array(3).String(tag).Bin(events)
+	}
+
+	options["size"] = len(items)
+
+	return message.Value(options), nil
+}
+
+func (writer *fluentWriter) record(
+	item fluentItem,
+) map[string]any {
+	if writer.options.raw {
+		return map[string]any{
+			"log": string(bytes.TrimRight(item.payload, "\n")),
+		}
+	}
+
+	record := map[string]any{}
+	for key, value := range item.entry.Ctx {
+		record[renameContextKey(key, item.entry.Ctx, writer.reserved)] = value
+	}
+	record["level"] = LevelName[item.entry.Level]
+	record["message"] = item.entry.Message
+	if item.entry.Channel != "" {
+		record["channel"] = item.entry.Channel
+	}
+
+	return record
+}
+
+func (*fluentWriter) reserved(
+	key string,
+) bool {
+	return key == "level" || key == "message" || key == "channel"
+}
+
+func (writer *fluentWriter) awaitAck(
+	chunk string,
+) error {
+	if writer.options.ackTimeout > 0 {
+		_ = writer.connection.SetReadDeadline(time.Now().Add(writer.options.ackTimeout))
+		defer func() { _ = writer.connection.SetReadDeadline(time.Time{}) }()
+	}
+
+	response, e := msgpackDecode(writer.connection)
+	if e != nil {
+		return e
+	}
+
+	if fields, ok := response.(map[string]any); !ok || fields["ack"] != chunk {
+		return newErrFluentAck(chunk)
+	}
+
+	return nil
+}
+
+func (writer *fluentWriter) handshake(
+	conn net.Conn,
+) error {
+	security := writer.options.security
+
+	if writer.options.ackTimeout > 0 {
+		_ = conn.SetDeadline(time.Now().Add(writer.options.ackTimeout))
+		defer func() { _ = conn.SetDeadline(time.Time{}) }()
+	}
+
+	response, e := msgpackDecode(conn)
+	if e != nil {
+		return e
+	}
+
+	helo, ok := response.([]any)
+	if !ok || len(helo) < 2 || helo[0] != "HELO" {
+		return newErrFluentHandshake("expected HELO")
+	}
+	heloOptions, _ := helo[1].(map[string]any)
+	nonce := fluentBytes(heloOptions["nonce"])
+	auth := fluentBytes(heloOptions["auth"])
+
+	salt := hex.EncodeToString(fluentRandom(16))
+	password := ""
+	if len(auth) != 0 {
+		password = fluentDigest(auth, []byte(security.username), []byte(security.password))
+	}
+
+	ping := msgpackBuffer{}.Array(6).
+		String("PING").
+		String(security.hostname).
+		String(salt).
+		String(fluentDigest([]byte(salt), []byte(security.hostname), nonce, []byte(security.sharedKey))).
+		String(security.username).
+		String(password)
+	if _, e := conn.Write(ping); e != nil {
+		return e
+	}
+
+	if response, e = msgpackDecode(conn); e != nil {
+		return e
+	}
+
+	pong, ok := response.([]any)
+	if !ok || len(pong) < 5 || pong[0] != "PONG" {
+		return newErrFluentHandshake("expected PONG")
+	}
+	if accepted, _ := pong[1].(bool); !accepted {
+		reason, _ := pong[2].(string)
+		return newErrFluentHandshake(reason)
+	}
+
+	hostname := fluentBytes(pong[3])
+	if string(fluentBytes(pong[4])) != fluentDigest([]byte(salt), hostname, nonce, []byte(security.sharedKey)) {
+		return newErrFluentHandshake("shared key mismatch")
+	}
+
+	return nil
+}
+
+func fluentRandom(
+	size int,
+) []byte {
+	data := make([]byte, size)
+	_, _ = rand.Read(data)
+
+	return data
+}
+
+func fluentDigest(
+	parts ...[]byte,
+) string {
+	hash := sha512.New()
+	for _, part := range parts {
+		_, _ = hash.Write(part)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func fluentBytes(
+	value any,
+) []byte {
+	switch typedValue := value.(type) {
+	case []byte:
+		return typedValue
+	case string:
+		return []byte(typedValue)
+	default:
+		return nil
+	}
+}
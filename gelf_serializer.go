@@ -0,0 +1,90 @@
+package log
+
+import (
+	"io"
+	"os"
+	"strings"
+)
+
+type gelfSerializer struct {
+	host string
+}
+
+func newGelfSerializer(
+	host string,
+) Serializer {
+	if host == "" {
+		host, _ = os.Hostname()
+	}
+
+	return &gelfSerializer{
+		host: host,
+	}
+}
+
+func (gelfSerializer) Close() error {
+	return nil
+}
+
+func (serializer gelfSerializer) Serialize(
+	writer io.Writer,
+	entry Entry,
+) error {
+	short, multiline := serializer.shortMessage(entry.Message)
+
+	level, ok := syslogSeverities[entry.Level]
+	if !ok {
+		level = syslogSeverities[Debug]
+	}
+
+	fields := []jsonField{
+		{key: "version", value: "1.1"},
+		{key: "host", value: serializer.host},
+		{key: "short_message", value: short},
+	}
+	if multiline {
+		fields = append(fields, jsonField{key: "full_message", value: entry.Message})
+	}
+	fields = append(fields,
+		jsonField{key: "timestamp", value: float64(entry.Timestamp.UnixMilli()) / 1000},
+		jsonField{key: "level", value: level})
+	if entry.Channel != "" {
+		fields = append(fields, jsonField{key: "_channel", value: entry.Channel})
+	}
+	for _, field := range flattenContext("", entry.Ctx) {
+		fields = append(fields, jsonField{key: serializer.key(field.key), value: field.value})
+	}
+
+	return jsonSerializer{}.write(&serializationWriter{writer: writer}, fields)
+}
+
+func (gelfSerializer) shortMessage(
+	message string,
+) (string, bool) {
+	multiline := strings.Contains(message, "\n")
+	for line := range strings.Lines(message) {
+		if line = strings.TrimSpace(line); line != "" {
+			return line, multiline
+		}
+	}
+
+	return GelfEmptyMessage, multiline
+}
+
+func (gelfSerializer) key(
+	key string,
+) string {
+	key = strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') ||
+			r == '_' || r == '.' || r == '-' {
+			return r
+		}
+		return '_'
+	}, key)
+
+	if key == "id" || key == "channel" {
+		return "_ctx_" + key
+	}
+
+	return "_" + key
+}
@@ -0,0 +1,23 @@
+package log
+
+import (
+	flam "github.com/happyhippyhippo/flam"
+)
+
+type gelfSerializerCreator struct{}
+
+func newGelfSerializerCreator() SerializerCreator {
+	return &gelfSerializerCreator{}
+}
+
+func (gelfSerializerCreator) Accept(
+	config flam.Bag,
+) bool {
+	return config.String("driver") == SerializerDriverGelf
+}
+
+func (gelfSerializerCreator) Create(
+	config flam.Bag,
+) (Serializer, error) {
+	return newGelfSerializer(config.String("host")), nil
+}
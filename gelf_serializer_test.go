@@ -0,0 +1,82 @@
+package log
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	flam "github.com/happyhippyhippo/flam"
+)
+
+func Test_gelfSerializer(t *testing.T) {
+	getSerializer := func(t *testing.T) Serializer {
+		serializer, e := getTestSerializer(t, "gelf", flam.Bag{
+			"driver": SerializerDriverGelf,
+			"host":   "host",
+		})
+		require.NoError(t, e)
+
+		return serializer
+	}
+
+	timestamp := time.Date(2021, 1, 2, 3, 4, 5, 6000000, time.UTC)
+
+	t.Run("should serialize the entry base fields", func(t *testing.T) {
+		serializer := getSerializer(t)
+
+		assert.Equal(
+			t,
+			`{"version":"1.1","host":"host","short_message":"message","timestamp":1609556645.006,"level":4}`+"\n",
+			serialize(t, serializer, Entry{Timestamp: timestamp, Level: Warning, Message: "message", Ctx: flam.Bag{}}))
+	})
+
+	t.Run("should split a multiline message into the short and full message", func(t *testing.T) {
+		serializer := getSerializer(t)
+
+		assert.Equal(
+			t,
+			`{"version":"1.1","host":"host","short_message":"first","full_message":"first\nsecond",`+
+				`"timestamp":1609556645.006,"level":3}`+"\n",
+			serialize(t, serializer, Entry{Timestamp: timestamp, Level: Error, Message: "first\nsecond", Ctx: flam.Bag{}}))
+	})
+
+	t.Run("should use the first non empty line as the short message", func(t *testing.T) {
+		serializer := getSerializer(t)
+
+		assert.Equal(
+			t,
+			`{"version":"1.1","host":"host","short_message":"first","full_message":"\n  \nfirst\nsecond",`+
+				`"timestamp":1609556645.006,"level":3}`+"\n",
+			serialize(t, serializer, Entry{Timestamp: timestamp, Level: Error, Message: "\n  \nfirst\nsecond", Ctx: flam.Bag{}}))
+	})
+
+	t.Run("should use a placeholder short message when the message is empty", func(t *testing.T) {
+		serializer := getSerializer(t)
+
+		assert.Equal(
+			t,
+			`{"version":"1.1","host":"host","short_message":"(empty)","timestamp":1609556645.006,"level":3}`+"\n",
+			serialize(t, serializer, Entry{Timestamp: timestamp, Level: Error, Message: " ", Ctx: flam.Bag{}}))
+	})
+
+	t.Run("should serialize the channel and context as additional fields", func(t *testing.T) {
+		serializer := getSerializer(t)
+
+		assert.Equal(
+			t,
+			`{"version":"1.1","host":"host","short_message":"message","timestamp":1609556645.006,"level":6,`+
+				`"_channel":"http","_ctx_id":12,"_request.path":"/","_user_name":"john"}`+"\n",
+			serialize(t, serializer, Entry{
+				Timestamp: timestamp,
+				Level:     Info,
+				Message:   "message",
+				Channel:   "http",
+				Ctx: flam.Bag{
+					"id":        12,
+					"request":   flam.Bag{"path": "/"},
+					"user name": "john",
+				}}))
+	})
+}
@@ -0,0 +1,81 @@
+package log
+
+import (
+	"sort"
+
+	flam "github.com/happyhippyhippo/flam"
+	filesystem "github.com/happyhippyhippo/flam-filesystem"
+)
+
+type gelfStreamCreator struct {
+	streamCreator
+
+	fileSystemFacade filesystem.Facade
+}
+
+func newGelfStreamCreator(
+	fileSystemFacade filesystem.Facade,
+	serializerFactory serializerFactory,
+) StreamCreator {
+	return &gelfStreamCreator{
+		streamCreator: streamCreator{
+			serializerFactory: serializerFactory,
+		},
+		fileSystemFacade: fileSystemFacade,
+	}
+}
+
+func (gelfStreamCreator) Accept(
+	config flam.Bag,
+) bool {
+	return config.String("driver") == StreamDriverGelf &&
+		config.Has("address")
+}
+
+func (creator gelfStreamCreator) Create(
+	config flam.Bag,
+) (Stream, error) {
+	serializer := newGelfSerializer(config.String("host"))
+	if serializerId := config.String("serializer"); serializerId != "" {
+		var e error
+		if serializer, e = creator.serializerFactory.Get(serializerId); e != nil {
+			return nil, e
+		}
+	}
+
+	tlsConfig, e := tlsConfigFrom(config.Bag("tls"), creator.fileSystemFacade)
+	if e != nil {
+		return nil, e
+	}
+
+	reconnect := config.Bag("reconnect", flam.Bag{})
+
+	writer, e := newGelfWriter(
+		newNetConnection(netConnectionOptions{
+			network: config.String("network", "udp"),
+			address: config.String("address"),
+			timeout: config.Duration("timeout", DefaultNetTimeout),
+			tls:     tlsConfig,
+			retry: retryPolicy{
+				backoff:    reconnect.Duration("backoff", DefaultRetryBackoff),
+				maxBackoff: reconnect.Duration("max_backoff", DefaultRetryMaxBackoff),
+			},
+		}),
+		gelfWriterOptions{
+			compress:  config.String("compress"),
+			chunkSize: config.Int("chunk_size", DefaultGelfChunkSize),
+		})
+	if e != nil {
+		return nil, e
+	}
+
+	channels := creator.getChannels(config.Slice("channels"))
+	sort.Strings(channels)
+
+	return newStream(
+		LevelFrom(config.Get("level"), DefaultLevel),
+		channels,
+		serializer,
+		writer,
+		true), nil
+}
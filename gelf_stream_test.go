@@ -0,0 +1,159 @@
+package log
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	flam "github.com/happyhippyhippo/flam"
+)
+
+func Test_gelfStream(t *testing.T) {
+	timestamp := time.Date(2021, 1, 2, 3, 4, 5, 6000000, time.UTC)
+	expected := `{"version":"1.1","host":"host","short_message":"message","timestamp":1609556645.006,"level":6,"_channel":"http"}`
+
+	t.Run("should return an error on unknown compression", func(t *testing.T) {
+		_, e := getDriverTestStream(t, StreamDriverGelf, flam.Bag{"address": "127.0.0.1:12201", "compress": "invalid"})
+
+		assert.ErrorIs(t, e, ErrUnknownCompression)
+	})
+
+	t.Run("should send an uncompressed message in a single datagram", func(t *testing.T) {
+		listener, read := listenTestUdp(t)
+
+		stream, e := getDriverTestStream(t, StreamDriverGelf, flam.Bag{"address": listener.LocalAddr().String()})
+		require.NoError(t, e)
+		defer func() { _ = stream.Close() }()
+
+		require.NoError(t, stream.Signal(timestamp, Info, "http", "message", flam.Bag{}))
+
+		assert.Equal(t, expected, string(read()))
+	})
+
+	t.Run("should compress the datagram", func(t *testing.T) {
+		for compression, decoder := range map[string]func(io.Reader) (io.Reader, error){
+			CompressionGzip: func(reader io.Reader) (io.Reader, error) { return gzip.NewReader(reader) },
+			CompressionZlib: func(reader io.Reader) (io.Reader, error) { return zlib.NewReader(reader) },
+		} {
+			listener, read := listenTestUdp(t)
+
+			stream, e := getDriverTestStream(t, StreamDriverGelf, flam.Bag{
+				"address":  listener.LocalAddr().String(),
+				"compress": compression,
+			})
+			require.NoError(t, e)
+
+			require.NoError(t, stream.Signal(timestamp, Info, "http", "message", flam.Bag{}))
+
+			reader, e := decoder(bytes.NewReader(read()))
+			require.NoError(t, e)
+			message, e := io.ReadAll(reader)
+			require.NoError(t, e)
+			assert.Equal(t, expected, string(message))
+
+			_ = stream.Close()
+		}
+	})
+
+	t.Run("should chunk the messages larger than the chunk size", func(t *testing.T) {
+		listener, read := listenTestUdp(t)
+
+		stream, e := getDriverTestStream(t, StreamDriverGelf, flam.Bag{
+			"address":    listener.LocalAddr().String(),
+			"chunk_size": 100,
+		})
+		require.NoError(t, e)
+		defer func() { _ = stream.Close() }()
+
+		message := strings.Repeat("x", 500)
+		require.NoError(t, stream.Broadcast(timestamp, Info, message, flam.Bag{}))
+
+		var id []byte
+		var payload []byte
+		for seq := 0; ; seq++ {
+			chunk := read()
+			require.LessOrEqual(t, len(chunk), 100)
+			require.Equal(t, []byte{0x1e, 0x0f}, chunk[:2])
+			if id == nil {
+				id = chunk[2:10]
+			}
+			assert.Equal(t, id, chunk[2:10])
+			assert.Equal(t, byte(seq), chunk[10])
+
+			payload = append(payload, chunk[12:]...)
+			if seq+1 == int(chunk[11]) {
+				break
+			}
+		}
+
+		assert.Contains(t, string(payload), `"short_message":"`+message+`"`)
+	})
+
+	t.Run("should return an error when the message requires too many chunks", func(t *testing.T) {
+		listener, _ := listenTestUdp(t)
+
+		stream, e := getDriverTestStream(t, StreamDriverGelf, flam.Bag{
+			"address":    listener.LocalAddr().String(),
+			"chunk_size": 20,
+		})
+		require.NoError(t, e)
+		defer func() { _ = stream.Close() }()
+
+		e = stream.Broadcast(timestamp, Info, strings.Repeat("x", 2000), flam.Bag{})
+
+		assert.ErrorIs(t, e, ErrGelfMessageTooLarge)
+	})
+
+	t.Run("should delimit the messages with a null byte over tcp", func(t *testing.T) {
+		listener, e := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, e)
+		defer func() { _ = listener.Close() }()
+
+		received := make(chan string, 2)
+		go func() {
+			conn, e := listener.Accept()
+			if e != nil {
+				return
+			}
+			defer func() { _ = conn.Close() }()
+
+			reader := bufio.NewReader(conn)
+			for range 2 {
+				message, e := reader.ReadString(0)
+				if e != nil {
+					return
+				}
+				received <- message
+			}
+		}()
+
+		stream, e := getDriverTestStream(t, StreamDriverGelf, flam.Bag{
+			"network":    "tcp",
+			"address":    listener.Addr().String(),
+			"serializer": "logfmt",
+		})
+		require.NoError(t, e)
+		defer func() { _ = stream.Close() }()
+
+		require.NoError(t, stream.Broadcast(timestamp, Info, "first", flam.Bag{}))
+		require.NoError(t, stream.Broadcast(timestamp, Info, "second", flam.Bag{}))
+
+		for _, message := range []string{"first", "second"} {
+			select {
+			case received := <-received:
+				assert.Equal(t, "time=2021-01-02T03:04:05.006+0000 level=info msg="+message+"\x00", received)
+			case <-time.After(time.Second):
+				assert.Fail(t, "message not received")
+			}
+		}
+	})
+}
@@ -0,0 +1,145 @@
+package log
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/binary"
+	"io"
+	"math/rand/v2"
+	"sync"
+)
+
+const (
+	gelfChunkHeaderSize = 12
+	gelfMaxChunks       = 128
+)
+
+type gelfWriterOptions struct {
+	compress  string
+	chunkSize int
+}
+
+type gelfWriter struct {
+	lock       sync.Locker
+	connection *netConnection
+	options    gelfWriterOptions
+}
+
+func newGelfWriter(
+	connection *netConnection,
+	options gelfWriterOptions,
+) (*gelfWriter, error) {
+	switch options.compress {
+	case "", CompressionGzip, CompressionZlib:
+	default:
+		return nil, newErrUnknownCompression(options.compress)
+	}
+
+	if options.chunkSize <= gelfChunkHeaderSize {
+		options.chunkSize = DefaultGelfChunkSize
+	}
+
+	return &gelfWriter{
+		lock:       &sync.Mutex{},
+		connection: connection,
+		options:    options,
+	}, nil
+}
+
+func (writer *gelfWriter) Write(
+	output []byte,
+) (int, error) {
+	message := bytes.TrimRight(output, "\n")
+
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+
+	var e error
+	if writer.connection.IsStream() {
+		e = writer.writeStream(message)
+	} else {
+		e = writer.writeDatagram(message)
+	}
+	if e != nil {
+		return 0, e
+	}
+
+	return len(output), nil
+}
+
+func (writer *gelfWriter) Close() error {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+
+	return writer.connection.Close()
+}
+
+func (writer *gelfWriter) writeStream(
+	message []byte,
+) error {
+	frame := make([]byte, 0, len(message)+1)
+	frame = append(frame, message...)
+	frame = append(frame, 0)
+
+	_, e := writer.connection.Write(frame)
+
+	return e
+}
+
+func (writer *gelfWriter) writeDatagram(
+	message []byte,
+) error {
+	message, e := writer.compress(message)
+	if e != nil {
+		return e
+	}
+
+	if len(message) <= writer.options.chunkSize {
+		_, e := writer.connection.Write(message)
+		return e
+	}
+
+	size := writer.options.chunkSize - gelfChunkHeaderSize
+	count := (len(message) + size - 1) / size
+	if count > gelfMaxChunks {
+		return newErrGelfMessageTooLarge(len(message))
+	}
+
+	id := binary.BigEndian.AppendUint64(nil, rand.Uint64())
+	for seq := 0; seq < count; seq++ {
+		chunk := make([]byte, 0, writer.options.chunkSize)
+		chunk = append(chunk, 0x1e, 0x0f)
+		chunk = append(chunk, id...)
+		chunk = append(chunk, byte(seq), byte(count))
+		chunk = append(chunk, message[seq*size:min((seq+1)*size, len(message))]...)
+
+		if _, e := writer.connection.Write(chunk); e != nil {
+			return e
+		}
+	}
+
+	return nil
+}
+
+func (writer *gelfWriter) compress(
+	message []byte,
+) ([]byte, error) {
+	var buffer bytes.Buffer
+	var encoder io.WriteCloser
+	switch writer.options.compress {
+	case CompressionGzip:
+		encoder = gzip.NewWriter(&buffer)
+	case CompressionZlib:
+		encoder = zlib.NewWriter(&buffer)
+	default:
+		return message, nil
+	}
+
+	_, _ = encoder.Write(message)
+	if e := encoder.Close(); e != nil {
+		return nil, e
+	}
+
+	return buffer.Bytes(), nil
+}
@@ -8,6 +8,7 @@ require (
 	github.com/happyhippyhippo/flam-config v0.3.0
 	github.com/happyhippyhippo/flam-filesystem v0.3.0
 	github.com/happyhippyhippo/flam-time v0.3.0
+	github.com/klauspost/compress v1.18.0
 	github.com/stretchr/testify v1.8.4
 	go.uber.org/dig v1.19.0
 )
@@ -0,0 +1,212 @@
+package log
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/dig"
+
+	flam "github.com/happyhippyhippo/flam"
+	config "github.com/happyhippyhippo/flam-config"
+	filesystem "github.com/happyhippyhippo/flam-filesystem"
+	flamTime "github.com/happyhippyhippo/flam-time"
+)
+
+func newTestContainer(
+	t *testing.T,
+	defaults flam.Bag,
+	provide ...func(container *dig.Container) error,
+) *dig.Container {
+	config.Defaults = flam.Bag{}
+	for path, value := range defaults {
+		_ = config.Defaults.Set(path, value)
+	}
+	t.Cleanup(func() { config.Defaults = flam.Bag{} })
+
+	container := dig.New()
+	require.NoError(t, flamTime.NewProvider().Register(container))
+	require.NoError(t, filesystem.NewProvider().Register(container))
+	require.NoError(t, config.NewProvider().Register(container))
+	require.NoError(t, NewProvider().Register(container))
+	for _, p := range provide {
+		require.NoError(t, p(container))
+	}
+	require.NoError(t, config.NewProvider().(flam.BootableProvider).Boot(container))
+
+	return container
+}
+
+func bootTestContainer(
+	t *testing.T,
+	defaults flam.Bag,
+	provide ...func(container *dig.Container) error,
+) *dig.Container {
+	container := newTestContainer(t, defaults, provide...)
+	require.NoError(t, NewProvider().(flam.BootableProvider).Boot(container))
+
+	return container
+}
+
+func getTestStream(
+	t *testing.T,
+	id string,
+	defaults flam.Bag,
+	provide ...func(container *dig.Container) error,
+) (Stream, error) {
+	defaults[PathBoot] = true
+
+	container := newTestContainer(t, defaults, provide...)
+	if e := NewProvider().(flam.BootableProvider).Boot(container); e != nil {
+		return nil, e
+	}
+
+	var stream Stream
+	e := container.Invoke(func(facade Facade) (e error) {
+		stream, e = facade.GetStream(id)
+		return e
+	})
+
+	return stream, e
+}
+
+var driverTestStreams = map[string]flam.Bag{
+	StreamDriverElasticsearch: {
+		"index":          "logs-%Y.%m.%d",
+		"id_key":         "request.id",
+		"batch_size":     2,
+		"batch_interval": 0,
+		"retry":          flam.Bag{"backoff": time.Millisecond},
+	},
+	StreamDriverFluent: {
+		"channels": []any{"*"},
+	},
+	StreamDriverGelf: {
+		"host":     "host",
+		"channels": []any{"*"},
+	},
+	StreamDriverHttp: {
+		"serializer": "json",
+		"retry":      flam.Bag{"backoff": time.Millisecond},
+	},
+	StreamDriverLoki: {
+		"channels":       []any{"*"},
+		"batch_interval": 0,
+		"retry":          flam.Bag{"backoff": time.Millisecond},
+	},
+	StreamDriverOtlp: {
+		"channels":       []any{"*"},
+		"batch_size":     1,
+		"batch_interval": 0,
+		"resource":       flam.Bag{"service": flam.Bag{"name": "app"}, "host": flam.Bag{"name": "host"}},
+	},
+	StreamDriverSocket: {
+		"serializer": "logfmt",
+		"reconnect":  flam.Bag{"backoff": time.Millisecond, "max_backoff": time.Millisecond},
+	},
+	StreamDriverSyslog: {
+		"app_name": "app",
+		"hostname": "host",
+		"channels": []any{"*"},
+	},
+}
+
+func getDriverTestStream(
+	t *testing.T,
+	driver string,
+	cfg flam.Bag,
+	provide ...func(container *dig.Container) error,
+) (Stream, error) {
+	streamCfg := flam.Bag{"driver": driver}
+	for key, value := range driverTestStreams[driver] {
+		streamCfg[key] = value
+	}
+	for key, value := range cfg {
+		streamCfg[key] = value
+	}
+
+	serializers := logfmtSerializers()
+	serializers["json"] = flam.Bag{"driver": SerializerDriverJson}
+
+	stream, e := getTestStream(t, "stream", flam.Bag{
+		filesystem.PathDisks: osDisks(),
+		PathSerializers:      serializers,
+		PathStreams:          flam.Bag{"stream": streamCfg}},
+		provide...)
+	if e == nil {
+		t.Cleanup(func() { _ = stream.Close() })
+	}
+
+	return stream, e
+}
+
+func listenTestUdp(
+	t *testing.T,
+) (net.PacketConn, func() []byte) {
+	listener, e := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, e)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	return listener, func() []byte {
+		buffer := make([]byte, 65536)
+		_ = listener.SetReadDeadline(time.Now().Add(time.Second))
+		n, _, e := listener.ReadFrom(buffer)
+		require.NoError(t, e)
+
+		return buffer[:n]
+	}
+}
+
+func getTestSerializer(
+	t *testing.T,
+	id string,
+	cfg flam.Bag,
+) (Serializer, error) {
+	container := newTestContainer(t, flam.Bag{PathSerializers: flam.Bag{id: cfg}})
+
+	var serializer Serializer
+	e := container.Invoke(func(facade Facade) (e error) {
+		serializer, e = facade.GetSerializer(id)
+		return e
+	})
+
+	return serializer, e
+}
+
+func logfmtSerializers() flam.Bag {
+	return flam.Bag{"logfmt": flam.Bag{"driver": SerializerDriverLogfmt}}
+}
+
+type testReport struct {
+	dropped int
+	e       error
+}
+
+func reportsOf(
+	stream Stream,
+) <-chan testReport {
+	reports := make(chan testReport, 16)
+	stream.(reportingWriter).report(func(dropped int, e error) {
+		reports <- testReport{dropped: dropped, e: e}
+	})
+
+	return reports
+}
+
+func nextReport(
+	t *testing.T,
+	reports <-chan testReport,
+) testReport {
+	select {
+	case report := <-reports:
+		return report
+	case <-time.After(time.Second):
+		require.Fail(t, "no delivery report")
+		return testReport{}
+	}
+}
+
+func osDisks() flam.Bag {
+	return flam.Bag{"os": flam.Bag{"driver": filesystem.DiskDriverOS}}
+}
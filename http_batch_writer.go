@@ -0,0 +1,403 @@
+package log
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+type httpBatchItem struct {
+	entry   Entry
+	payload []byte
+}
+
+type httpBatchCodec interface {
+	Encode(items []httpBatchItem) (string, []byte, error)
+	Decode(items []httpBatchItem, status int, body []byte) ([]httpBatchItem, int, error)
+}
+
+type httpBatchWriterOptions struct {
+	url        string
+	method     string
+	headers    map[string]string
+	compress   string
+	batchSize  int
+	interval   time.Duration
+	timeout    time.Duration
+	maxRetries int
+	retry      retryPolicy
+}
+
+type httpBatchWriter struct {
+	lock     sync.Locker
+	client   *http.Client
+	codec    httpBatchCodec
+	options  httpBatchWriterOptions
+	batch    []httpBatchItem
+	dropped  int
+	reporter func(dropped int, e error)
+	wake     chan struct{}
+	stop     chan struct{}
+	done     chan struct{}
+	closing  sync.Once
+	closeErr error
+}
+
+func newHttpBatchWriter(
+	codec httpBatchCodec,
+	options httpBatchWriterOptions,
+) (*httpBatchWriter, error) {
+	switch options.compress {
+	case "", CompressionGzip:
+	default:
+		return nil, newErrUnknownCompression(options.compress)
+	}
+
+	if options.method == "" {
+		options.method = http.MethodPost
+	}
+
+	writer := &httpBatchWriter{
+		lock:    &sync.Mutex{},
+		client:  &http.Client{Timeout: options.timeout},
+		codec:   codec,
+		options: options,
+		wake:    make(chan struct{}, 1),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+
+	go writer.run()
+
+	return writer, nil
+}
+
+func (writer *httpBatchWriter) Write(
+	output []byte,
+) (int, error) {
+	e := writer.writeEntry(Entry{
+		Timestamp: time.Now(),
+		Level:     Info,
+		Message:   string(output),
+	}, output)
+	if e != nil {
+		return 0, e
+	}
+
+	return len(output), nil
+}
+
+func (writer *httpBatchWriter) Close() error {
+	writer.closing.Do(func() {
+		close(writer.stop)
+		<-writer.done
+
+		_, writer.closeErr = writer.flush(nil)
+
+		writer.lock.Lock()
+		writer.dropped += len(writer.batch)
+		writer.batch = nil
+		writer.lock.Unlock()
+		writer.notify(nil)
+	})
+
+	return writer.closeErr
+}
+
+func (writer *httpBatchWriter) report(
+	reporter func(dropped int, e error),
+) {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+
+	writer.reporter = reporter
+}
+
+func (writer *httpBatchWriter) writeEntry(
+	entry Entry,
+	payload []byte,
+) error {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+
+	writer.batch = append(writer.batch, httpBatchItem{
+		entry:   entry,
+		payload: append([]byte(nil), payload...),
+	})
+	writer.trim()
+
+	if writer.options.batchSize <= 0 || len(writer.batch) >= writer.options.batchSize {
+		select {
+		case writer.wake <- struct{}{}:
+		default:
+		}
+	}
+
+	return nil
+}
+
+func (writer *httpBatchWriter) run() {
+	defer close(writer.done)
+
+	var tick <-chan time.Time
+	if writer.options.interval > 0 {
+		ticker := time.NewTicker(writer.options.interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	retry := time.NewTimer(time.Hour)
+	retry.Stop()
+	defer retry.Stop()
+
+	failures := 0
+	for {
+		select {
+		case <-writer.stop:
+			return
+		case <-writer.wake:
+			if failures != 0 {
+				continue
+			}
+		case <-tick:
+			if failures != 0 {
+				continue
+			}
+		case <-retry.C:
+		}
+
+		requeued, e := writer.flush(writer.stop)
+		writer.notify(e)
+		if !requeued {
+			failures = 0
+			continue
+		}
+
+		failures++
+		retry.Reset(max(writer.options.retry.delay(failures), minRetryInterval))
+	}
+}
+
+func (writer *httpBatchWriter) flush(
+	stop <-chan struct{},
+) (bool, error) {
+	var errs []error
+	for {
+		writer.lock.Lock()
+		count := len(writer.batch)
+		if writer.options.batchSize > 0 {
+			count = min(count, writer.options.batchSize)
+		}
+		items := writer.batch[:count:count]
+		writer.batch = writer.batch[count:]
+		writer.lock.Unlock()
+
+		if len(items) == 0 {
+			return false, errors.Join(errs...)
+		}
+
+		failed, e := writer.send(items, stop)
+		if e != nil {
+			errs = append(errs, e)
+		}
+		if len(failed) != 0 {
+			writer.lock.Lock()
+			writer.batch = append(failed, writer.batch...)
+			writer.trim()
+			writer.lock.Unlock()
+
+			return true, errors.Join(errs...)
+		}
+	}
+}
+
+func (writer *httpBatchWriter) trim() {
+	limit := writer.options.retry.maxPending
+	if limit <= 0 || len(writer.batch) <= limit {
+		return
+	}
+
+	over := len(writer.batch) - limit
+	writer.dropped += over
+	writer.batch = append(writer.batch[:0], writer.batch[over:]...)
+}
+
+func (writer *httpBatchWriter) discard(
+	dropped int,
+) {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+
+	writer.dropped += dropped
+}
+
+func (writer *httpBatchWriter) notify(
+	e error,
+) {
+	writer.lock.Lock()
+	dropped := writer.dropped
+	reporter := writer.reporter
+	if reporter != nil {
+		writer.dropped = 0
+	}
+	writer.lock.Unlock()
+
+	if reporter != nil && (dropped != 0 || e != nil) {
+		reporter(dropped, e)
+	}
+}
+
+func (writer *httpBatchWriter) send(
+	items []httpBatchItem,
+	stop <-chan struct{},
+) ([]httpBatchItem, error) {
+	for attempt := 1; ; attempt++ {
+		status, body, wait, e := writer.post(items)
+		if e == nil && status != http.StatusTooManyRequests && status < http.StatusInternalServerError {
+			var retry []httpBatchItem
+			var dropped int
+			retry, dropped, e = writer.codec.Decode(items, status, body)
+			writer.discard(dropped)
+			if len(retry) == 0 {
+				return nil, e
+			}
+			if e == nil {
+				e = newErrHttpStatus(status)
+			}
+			items = retry
+		} else if e == nil {
+			e = newErrHttpStatus(status)
+		}
+
+		if attempt > writer.options.maxRetries {
+			return items, e
+		}
+
+		if wait <= 0 {
+			wait = writer.options.retry.delay(attempt)
+		}
+		if maxBackoff := writer.options.retry.maxBackoff; maxBackoff > 0 {
+			wait = min(wait, maxBackoff)
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-stop:
+			timer.Stop()
+			return items, e
+		case <-timer.C:
+		}
+	}
+}
+
+func (writer *httpBatchWriter) post(
+	items []httpBatchItem,
+) (int, []byte, time.Duration, error) {
+	contentType, body, e := writer.codec.Encode(items)
+	if e != nil {
+		return 0, nil, 0, e
+	}
+
+	if writer.options.compress == CompressionGzip {
+		compressed := &bytes.Buffer{}
+		encoder := gzip.NewWriter(compressed)
+		_, _ = encoder.Write(body)
+		if e := encoder.Close(); e != nil {
+			return 0, nil, 0, e
+		}
+		body = compressed.Bytes()
+	}
+
+	request, e := http.NewRequest(writer.options.method, writer.options.url, bytes.NewReader(body))
+	if e != nil {
+		return 0, nil, 0, e
+	}
+
+	request.Header.Set("Content-Type", contentType)
+	if writer.options.compress == CompressionGzip {
+		request.Header.Set("Content-Encoding", "gzip")
+	}
+	for key, value := range writer.options.headers {
+		request.Header.Set(key, value)
+	}
+
+	response, e := writer.client.Do(request)
+	if e != nil {
+		return 0, nil, 0, e
+	}
+	defer func() { _ = response.Body.Close() }()
+
+	responseBody, e := io.ReadAll(response.Body)
+	if e != nil {
+		return 0, nil, 0, e
+	}
+
+	var wait time.Duration
+	if seconds, e := strconv.Atoi(response.Header.Get("Retry-After")); e == nil {
+		wait = time.Duration(seconds) * time.Second
+	}
+
+	return response.StatusCode, responseBody, wait, nil
+}
+
+type httpPayloadCodec struct {
+	format string
+}
+
+func newHttpPayloadCodec(
+	format string,
+) (httpBatchCodec, error) {
+	switch format {
+	case "":
+		format = HttpFormatNdjson
+	case HttpFormatNdjson, HttpFormatJsonArray:
+	default:
+		return nil, newErrUnknownHttpFormat(format)
+	}
+
+	return &httpPayloadCodec{
+		format: format,
+	}, nil
+}
+
+func (codec httpPayloadCodec) Encode(
+	items []httpBatchItem,
+) (string, []byte, error) {
+	body := &bytes.Buffer{}
+	if codec.format == HttpFormatJsonArray {
+		_ = body.WriteByte('[')
+		for i, item := range items {
+			if i != 0 {
+				_ = body.WriteByte(',')
+			}
+			_, _ = body.Write(bytes.TrimRight(item.payload, "\n"))
+		}
+		_ = body.WriteByte(']')
+
+		return "application/json", body.Bytes(), nil
+	}
+
+	for _, item := range items {
+		_, _ = body.Write(bytes.TrimRight(item.payload, "\n"))
+		_ = body.WriteByte('\n')
+	}
+
+	return "application/x-ndjson", body.Bytes(), nil
+}
+
+func (httpPayloadCodec) Decode(
+	items []httpBatchItem,
+	status int,
+	_ []byte,
+) ([]httpBatchItem, int, error) {
+	if status < http.StatusOK || status >= http.StatusMultipleChoices {
+		return nil, len(items), newErrHttpStatus(status)
+	}
+
+	return nil, 0, nil
+}
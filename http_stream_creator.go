@@ -0,0 +1,87 @@
+package log
+
+import (
+	"sort"
+
+	flam "github.com/happyhippyhippo/flam"
+)
+
+type httpStreamCreator struct {
+	streamCreator
+}
+
+func newHttpStreamCreator(
+	serializerFactory serializerFactory,
+) StreamCreator {
+	return &httpStreamCreator{
+		streamCreator: streamCreator{
+			serializerFactory: serializerFactory,
+		},
+	}
+}
+
+func (httpStreamCreator) Accept(
+	config flam.Bag,
+) bool {
+	return config.String("driver") == StreamDriverHttp &&
+		config.Has("url")
+}
+
+func (creator httpStreamCreator) Create(
+	config flam.Bag,
+) (Stream, error) {
+	serializerId := config.String("serializer", DefaultSerializer)
+	serializer, e := creator.serializerFactory.Get(serializerId)
+	if e != nil {
+		return nil, e
+	}
+
+	codec, e := newHttpPayloadCodec(config.String("format"))
+	if e != nil {
+		return nil, e
+	}
+
+	writer, e := newHttpBatchWriter(codec, httpBatchWriterOptionsFrom(config))
+	if e != nil {
+		return nil, e
+	}
+
+	channels := creator.getChannels(config.Slice("channels"))
+	sort.Strings(channels)
+
+	return newStream(
+		LevelFrom(config.Get("level"), DefaultLevel),
+		channels,
+		serializer,
+		writer,
+		true), nil
+}
+
+func httpBatchWriterOptionsFrom(
+	config flam.Bag,
+) httpBatchWriterOptions {
+	headers := map[string]string{}
+	for key, value := range config.Bag("headers") {
+		if typedValue, ok := value.(string); ok {
+			headers[key] = typedValue
+		}
+	}
+
+	retry := config.Bag("retry", flam.Bag{})
+
+	return httpBatchWriterOptions{
+		url:        config.String("url"),
+		method:     config.String("method"),
+		headers:    headers,
+		compress:   config.String("compress"),
+		batchSize:  config.Int("batch_size", DefaultHttpBatchSize),
+		interval:   config.Duration("batch_interval", DefaultHttpBatchInterval),
+		timeout:    config.Duration("timeout", DefaultHttpTimeout),
+		maxRetries: retry.Int("max_retries", DefaultHttpMaxRetries),
+		retry: retryPolicy{
+			backoff:    retry.Duration("backoff", DefaultRetryBackoff),
+			maxBackoff: retry.Duration("max_backoff", DefaultRetryMaxBackoff),
+			maxPending: retry.Int("max_pending", DefaultRetryMaxPending),
+		},
+	}
+}
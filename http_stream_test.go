@@ -0,0 +1,274 @@
+package log
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	flam "github.com/happyhippyhippo/flam"
+)
+
+type httpRecorder struct {
+	mutex      sync.Mutex
+	requests   []*http.Request
+	bodies     []string
+	statuses   []int
+	replies    []string
+	retryAfter string
+}
+
+func (recorder *httpRecorder) ServeHTTP(
+	writer http.ResponseWriter,
+	request *http.Request,
+) {
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+
+	reader := io.Reader(request.Body)
+	if request.Header.Get("Content-Encoding") == "gzip" {
+		reader, _ = gzip.NewReader(request.Body)
+	}
+	body, _ := io.ReadAll(reader)
+
+	recorder.requests = append(recorder.requests, request)
+	recorder.bodies = append(recorder.bodies, string(body))
+
+	status := http.StatusOK
+	if len(recorder.statuses) != 0 {
+		status = recorder.statuses[0]
+		recorder.statuses = recorder.statuses[1:]
+	}
+	if recorder.retryAfter != "" {
+		writer.Header().Set("Retry-After", recorder.retryAfter)
+	}
+	writer.WriteHeader(status)
+
+	if len(recorder.replies) != 0 {
+		_, _ = writer.Write([]byte(recorder.replies[0]))
+		recorder.replies = recorder.replies[1:]
+	}
+}
+
+func (recorder *httpRecorder) Bodies() []string {
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+
+	return append([]string(nil), recorder.bodies...)
+}
+
+func (recorder *httpRecorder) Await(
+	t *testing.T,
+	count int,
+) []string {
+	require.Eventually(t, func() bool {
+		return len(recorder.Bodies()) >= count
+	}, time.Second, time.Millisecond)
+
+	return recorder.Bodies()
+}
+
+func Test_httpStream(t *testing.T) {
+	timestamp := time.Date(2021, 1, 2, 3, 4, 5, 6000000, time.UTC)
+	line := func(message string) string {
+		return `{"level":"INFO","message":"` + message + `","time":"2021-01-02T03:04:05.006+0000"}`
+	}
+
+	t.Run("should return an error on unknown format", func(t *testing.T) {
+		_, e := getDriverTestStream(t, StreamDriverHttp, flam.Bag{"url": "http://localhost", "format": "invalid"})
+
+		assert.ErrorIs(t, e, ErrUnknownHttpFormat)
+	})
+
+	t.Run("should return an error on unknown compression", func(t *testing.T) {
+		_, e := getDriverTestStream(t, StreamDriverHttp, flam.Bag{"url": "http://localhost", "compress": "invalid"})
+
+		assert.ErrorIs(t, e, ErrUnknownCompression)
+	})
+
+	t.Run("should post a ndjson batch when the batch size is reached", func(t *testing.T) {
+		recorder := &httpRecorder{}
+		server := httptest.NewServer(recorder)
+		defer server.Close()
+
+		stream, e := getDriverTestStream(t, StreamDriverHttp, flam.Bag{
+			"url":            server.URL,
+			"headers":        flam.Bag{"Authorization": "token"},
+			"batch_size":     2,
+			"batch_interval": 0,
+		})
+		require.NoError(t, e)
+
+		require.NoError(t, stream.Broadcast(timestamp, Info, "message1", flam.Bag{}))
+		assert.Empty(t, recorder.Bodies())
+		require.NoError(t, stream.Broadcast(timestamp, Info, "message2", flam.Bag{}))
+
+		recorder.Await(t, 1)
+		assert.Equal(t, []string{line("message1") + "\n" + line("message2") + "\n"}, recorder.Bodies())
+		assert.Equal(t, "application/x-ndjson", recorder.requests[0].Header.Get("Content-Type"))
+		assert.Equal(t, "token", recorder.requests[0].Header.Get("Authorization"))
+		assert.NoError(t, stream.Close())
+	})
+
+	t.Run("should post a gzip json array on close", func(t *testing.T) {
+		recorder := &httpRecorder{}
+		server := httptest.NewServer(recorder)
+		defer server.Close()
+
+		stream, e := getDriverTestStream(t, StreamDriverHttp, flam.Bag{
+			"url":      server.URL,
+			"format":   HttpFormatJsonArray,
+			"compress": CompressionGzip,
+		})
+		require.NoError(t, e)
+
+		require.NoError(t, stream.Broadcast(timestamp, Info, "message1", flam.Bag{}))
+		require.NoError(t, stream.Broadcast(timestamp, Info, "message2", flam.Bag{}))
+		require.NoError(t, stream.Close())
+
+		assert.Equal(t, []string{"[" + line("message1") + "," + line("message2") + "]"}, recorder.Bodies())
+		assert.Equal(t, "application/json", recorder.requests[0].Header.Get("Content-Type"))
+		assert.Equal(t, "gzip", recorder.requests[0].Header.Get("Content-Encoding"))
+	})
+
+	t.Run("should return the first close error on the later closes", func(t *testing.T) {
+		recorder := &httpRecorder{statuses: []int{http.StatusBadRequest}}
+		server := httptest.NewServer(recorder)
+		defer server.Close()
+
+		stream, e := getDriverTestStream(t, StreamDriverHttp, flam.Bag{"url": server.URL})
+		require.NoError(t, e)
+
+		require.NoError(t, stream.Broadcast(timestamp, Info, "message", flam.Bag{}))
+		e = stream.Close()
+		assert.ErrorIs(t, e, ErrHttpStatus)
+		assert.Equal(t, e, stream.Close())
+
+		assert.Len(t, recorder.Bodies(), 1)
+	})
+
+	t.Run("should post the batch on the batch interval", func(t *testing.T) {
+		recorder := &httpRecorder{}
+		server := httptest.NewServer(recorder)
+		defer server.Close()
+
+		stream, e := getDriverTestStream(t, StreamDriverHttp, flam.Bag{
+			"url":            server.URL,
+			"batch_interval": 5,
+		})
+		require.NoError(t, e)
+		defer func() { _ = stream.Close() }()
+
+		require.NoError(t, stream.Broadcast(timestamp, Info, "message", flam.Bag{}))
+
+		recorder.Await(t, 1)
+	})
+
+	t.Run("should retry the batch on server errors and throttling", func(t *testing.T) {
+		recorder := &httpRecorder{statuses: []int{http.StatusServiceUnavailable, http.StatusTooManyRequests}}
+		server := httptest.NewServer(recorder)
+		defer server.Close()
+
+		stream, e := getDriverTestStream(t, StreamDriverHttp, flam.Bag{
+			"url":        server.URL,
+			"batch_size": 1,
+		})
+		require.NoError(t, e)
+		defer func() { _ = stream.Close() }()
+
+		require.NoError(t, stream.Broadcast(timestamp, Info, "message", flam.Bag{}))
+
+		recorder.Await(t, 3)
+		assert.Equal(t, []string{line("message") + "\n", line("message") + "\n", line("message") + "\n"}, recorder.Bodies())
+	})
+
+	t.Run("should report the error and requeue the batch when the retries are exhausted", func(t *testing.T) {
+		recorder := &httpRecorder{statuses: []int{http.StatusBadGateway, http.StatusBadGateway}}
+		server := httptest.NewServer(recorder)
+		defer server.Close()
+
+		stream, e := getDriverTestStream(t, StreamDriverHttp, flam.Bag{
+			"url":        server.URL,
+			"batch_size": 1,
+			"retry":      flam.Bag{"max_retries": 1, "backoff": time.Millisecond},
+		})
+		require.NoError(t, e)
+		defer func() { _ = stream.Close() }()
+		reports := reportsOf(stream)
+
+		assert.NoError(t, stream.Broadcast(timestamp, Info, "message", flam.Bag{}))
+		assert.ErrorIs(t, nextReport(t, reports).e, ErrHttpStatus)
+
+		recorder.Await(t, 3)
+		assert.Equal(t, line("message")+"\n", recorder.Bodies()[2])
+	})
+
+	t.Run("should drop the oldest entries over the pending limit", func(t *testing.T) {
+		recorder := &httpRecorder{statuses: []int{http.StatusBadGateway}}
+		server := httptest.NewServer(recorder)
+		defer server.Close()
+
+		stream, e := getDriverTestStream(t, StreamDriverHttp, flam.Bag{
+			"url":            server.URL,
+			"batch_size":     2,
+			"batch_interval": 0,
+			"retry":          flam.Bag{"max_retries": 0, "backoff": time.Hour, "max_pending": 2},
+		})
+		require.NoError(t, e)
+		reports := reportsOf(stream)
+
+		require.NoError(t, stream.Broadcast(timestamp, Info, "message1", flam.Bag{}))
+		require.NoError(t, stream.Broadcast(timestamp, Info, "message2", flam.Bag{}))
+		assert.ErrorIs(t, nextReport(t, reports).e, ErrHttpStatus)
+
+		require.NoError(t, stream.Broadcast(timestamp, Info, "message3", flam.Bag{}))
+		require.NoError(t, stream.Close())
+
+		assert.Equal(t, 1, nextReport(t, reports).dropped)
+		assert.Equal(t, line("message2")+"\n"+line("message3")+"\n", recorder.Bodies()[1])
+	})
+
+	t.Run("should cap the retry after wait at the max backoff", func(t *testing.T) {
+		recorder := &httpRecorder{statuses: []int{http.StatusTooManyRequests}, retryAfter: "3600"}
+		server := httptest.NewServer(recorder)
+		defer server.Close()
+
+		stream, e := getDriverTestStream(t, StreamDriverHttp, flam.Bag{
+			"url":        server.URL,
+			"batch_size": 1,
+			"retry":      flam.Bag{"backoff": time.Millisecond, "max_backoff": time.Millisecond},
+		})
+		require.NoError(t, e)
+		defer func() { _ = stream.Close() }()
+
+		require.NoError(t, stream.Broadcast(timestamp, Info, "message", flam.Bag{}))
+
+		recorder.Await(t, 2)
+	})
+
+	t.Run("should report the client errors without retrying", func(t *testing.T) {
+		recorder := &httpRecorder{statuses: []int{http.StatusBadRequest}}
+		server := httptest.NewServer(recorder)
+		defer server.Close()
+
+		stream, e := getDriverTestStream(t, StreamDriverHttp, flam.Bag{
+			"url":        server.URL,
+			"batch_size": 1,
+		})
+		require.NoError(t, e)
+		defer func() { _ = stream.Close() }()
+		reports := reportsOf(stream)
+
+		assert.NoError(t, stream.Broadcast(timestamp, Info, "message", flam.Bag{}))
+		report := nextReport(t, reports)
+		assert.Equal(t, 1, report.dropped)
+		assert.ErrorIs(t, report.e, ErrHttpStatus)
+		assert.Len(t, recorder.Bodies(), 1)
+	})
+}
@@ -2,33 +2,398 @@ package log
 
 import (
 	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
+	"unicode/utf8"
 
 	flam "github.com/happyhippyhippo/flam"
 )
 
-type jsonSerializer struct{}
+const jsonDefaultTimeLayout = "2006-01-02T15:04:05.000-0700"
 
-func newJsonSerializer() Serializer {
-	return &jsonSerializer{}
+var jsonLayouts = map[string]jsonSerializerOptions{
+	JsonLayoutDefault: {
+		timeField:    "time",
+		levelField:   "level",
+		messageField: "message",
+		channelField: "channel",
+		timeLayout:   jsonDefaultTimeLayout,
+	},
+	JsonLayoutEcs: {
+		timeField:    "@timestamp",
+		levelField:   "log.level",
+		messageField: "message",
+		channelField: "log.logger",
+		timeLayout:   "2006-01-02T15:04:05.000Z07:00",
+		lowerLevel:   true,
+	},
+}
+
+type jsonFieldKind int
+
+const (
+	jsonFieldValue jsonFieldKind = iota
+	jsonFieldText
+	jsonFieldTime
+)
+
+type jsonField struct {
+	key   string
+	value any
+	kind  jsonFieldKind
+	text  string
+}
+
+type jsonScratch struct {
+	fields []jsonField
+	buffer []byte
+}
+
+const jsonScratchMaxBuffer = 64 * 1024
+
+var jsonScratchPool = sync.Pool{
+	New: func() any {
+		return &jsonScratch{}
+	},
+}
+
+func releaseJsonScratch(
+	scratch *jsonScratch,
+) {
+	if cap(scratch.buffer) > jsonScratchMaxBuffer {
+		return
+	}
+
+	clear(scratch.fields)
+	scratch.fields = scratch.fields[:0]
+	jsonScratchPool.Put(scratch)
+}
+
+var upperLevelName = func() map[Level]string {
+	names := map[Level]string{}
+	for level, name := range LevelName {
+		names[level] = strings.ToUpper(name)
+	}
+	return names
+}()
+
+type jsonSerializerOptions struct {
+	layout       string
+	timeField    string
+	levelField   string
+	messageField string
+	channelField string
+	timeLayout   string
+	lowerLevel   bool
+}
+
+type jsonSerializer struct {
+	options jsonSerializerOptions
+}
+
+func newJsonSerializer(
+	options jsonSerializerOptions,
+) (Serializer, error) {
+	if options.layout == "" {
+		options.layout = JsonLayoutDefault
+	}
+
+	layout, ok := jsonLayouts[options.layout]
+	if !ok {
+		return nil, newErrInvalidSerializerFormat(options.layout)
+	}
+
+	if options.timeField == "" {
+		options.timeField = layout.timeField
+	}
+	if options.levelField == "" {
+		options.levelField = layout.levelField
+	}
+	if options.messageField == "" {
+		options.messageField = layout.messageField
+	}
+	if options.channelField == "" {
+		options.channelField = layout.channelField
+	}
+	if options.timeLayout == "" {
+		options.timeLayout = layout.timeLayout
+	}
+	options.lowerLevel = options.lowerLevel || layout.lowerLevel
+
+	return &jsonSerializer{
+		options: options,
+	}, nil
 }
 
 func (jsonSerializer) Close() error {
 	return nil
 }
 
-func (jsonSerializer) Serialize(
-	timestamp time.Time,
-	level Level,
-	message string,
-	ctx flam.Bag,
-) string {
-	ctx["time"] = timestamp.Format("2006-01-02T15:04:05.000-0700")
-	ctx["level"] = strings.ToUpper(LevelName[level])
-	ctx["message"] = message
-	bytes, _ := json.Marshal(ctx)
-	bytes = append(bytes, '\n')
+func (serializer jsonSerializer) Serialize(
+	writer io.Writer,
+	entry Entry,
+) error {
+	level := upperLevelName[entry.Level]
+	if serializer.options.lowerLevel {
+		level = LevelName[entry.Level]
+	}
+
+	scratch := jsonScratchPool.Get().(*jsonScratch)
+	defer releaseJsonScratch(scratch)
+
+	fields := append(scratch.fields[:0],
+		jsonField{key: serializer.options.timeField, kind: jsonFieldTime},
+		jsonField{key: serializer.options.levelField, kind: jsonFieldText, text: level},
+		jsonField{key: serializer.options.messageField, kind: jsonFieldText, text: entry.Message})
+	if entry.Channel != "" {
+		fields = append(fields, jsonField{key: serializer.options.channelField, kind: jsonFieldText, text: entry.Channel})
+	}
+	for key, value := range entry.Ctx {
+		fields = append(fields, jsonField{key: renameContextKey(key, entry.Ctx, serializer.reserved), value: value})
+	}
+	slices.SortFunc(fields, func(a, b jsonField) int {
+		return strings.Compare(a.key, b.key)
+	})
+	scratch.fields = fields
+
+	scratch.buffer = serializer.appendObject(scratch.buffer[:0], fields, entry)
+	_, e := writer.Write(scratch.buffer)
+
+	return e
+}
+
+func (serializer jsonSerializer) reserved(
+	key string,
+) bool {
+	switch key {
+	case serializer.options.timeField,
+		serializer.options.levelField,
+		serializer.options.messageField,
+		serializer.options.channelField:
+		return true
+	default:
+		return isReservedKey(key)
+	}
+}
+
+func (serializer jsonSerializer) write(
+	writer *serializationWriter,
+	fields []jsonField,
+) error {
+	scratch := jsonScratchPool.Get().(*jsonScratch)
+	defer releaseJsonScratch(scratch)
+
+	scratch.buffer = serializer.appendObject(scratch.buffer[:0], fields, Entry{})
+	_, _ = writer.Write(scratch.buffer)
+
+	return writer.e
+}
+
+func (serializer jsonSerializer) appendObject(
+	buffer []byte,
+	fields []jsonField,
+	entry Entry,
+) []byte {
+	buffer = append(buffer, '{')
+	for i, field := range fields {
+		if i != 0 {
+			buffer = append(buffer, ',')
+		}
+
+		buffer = appendJsonString(buffer, field.key)
+		buffer = append(buffer, ':')
+
+		switch field.kind {
+		case jsonFieldText:
+			buffer = appendJsonString(buffer, field.text)
+		case jsonFieldTime:
+			buffer = append(buffer, '"')
+			buffer = entry.Timestamp.AppendFormat(buffer, serializer.options.timeLayout)
+			buffer = append(buffer, '"')
+		default:
+			buffer = appendJsonValue(buffer, field.value)
+		}
+	}
+
+	return append(buffer, '}', '\n')
+}
+
+func appendJsonValue(
+	buffer []byte,
+	value any,
+) []byte {
+	switch v := value.(type) {
+	case nil:
+		return append(buffer, "null"...)
+	case string:
+		return appendJsonString(buffer, v)
+	case bool:
+		return strconv.AppendBool(buffer, v)
+	case int:
+		return strconv.AppendInt(buffer, int64(v), 10)
+	case int8:
+		return strconv.AppendInt(buffer, int64(v), 10)
+	case int16:
+		return strconv.AppendInt(buffer, int64(v), 10)
+	case int32:
+		return strconv.AppendInt(buffer, int64(v), 10)
+	case int64:
+		return strconv.AppendInt(buffer, v, 10)
+	case time.Duration:
+		return strconv.AppendInt(buffer, int64(v), 10)
+	case uint:
+		return strconv.AppendUint(buffer, uint64(v), 10)
+	case uint8:
+		return strconv.AppendUint(buffer, uint64(v), 10)
+	case uint16:
+		return strconv.AppendUint(buffer, uint64(v), 10)
+	case uint32:
+		return strconv.AppendUint(buffer, uint64(v), 10)
+	case uint64:
+		return strconv.AppendUint(buffer, v, 10)
+	case float32:
+		return appendJsonFloat(buffer, float64(v), 32)
+	case float64:
+		return appendJsonFloat(buffer, v, 64)
+	case time.Time:
+		buffer = append(buffer, '"')
+		buffer = v.AppendFormat(buffer, time.RFC3339Nano)
+		return append(buffer, '"')
+	case error:
+		return appendJsonString(buffer, v.Error())
+	case flam.Bag:
+		return appendJsonMap(buffer, v)
+	case *flam.Bag:
+		return appendJsonMap(buffer, *v)
+	case map[string]any:
+		return appendJsonMap(buffer, v)
+	case []any:
+		buffer = append(buffer, '[')
+		for i, item := range v {
+			if i != 0 {
+				buffer = append(buffer, ',')
+			}
+			buffer = appendJsonValue(buffer, item)
+		}
+		return append(buffer, ']')
+	default:
+		data, e := json.Marshal(v)
+		if e != nil {
+			return appendJsonString(buffer, fmt.Sprint(v))
+		}
+		return append(buffer, data...)
+	}
+}
+
+func appendJsonMap(
+	buffer []byte,
+	value map[string]any,
+) []byte {
+	var stack [16]string
+	keys := stack[:0]
+	for key := range value {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	buffer = append(buffer, '{')
+	for i, key := range keys {
+		if i != 0 {
+			buffer = append(buffer, ',')
+		}
+		buffer = appendJsonString(buffer, key)
+		buffer = append(buffer, ':')
+		buffer = appendJsonValue(buffer, value[key])
+	}
+
+	return append(buffer, '}')
+}
+
+func appendJsonFloat(
+	buffer []byte,
+	value float64,
+	bits int,
+) []byte {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return appendJsonString(buffer, fmt.Sprint(value))
+	}
+
+	format := byte('f')
+	if abs := math.Abs(value); abs != 0 {
+		if bits == 64 && (abs < 1e-6 || abs >= 1e21) || bits == 32 && (float32(abs) < 1e-6 || float32(abs) >= 1e21) {
+			format = 'e'
+		}
+	}
+
+	start := len(buffer)
+	buffer = strconv.AppendFloat(buffer, value, format, -1, bits)
+	if format == 'e' {
+		if n := len(buffer) - start; n >= 4 && buffer[len(buffer)-4] == 'e' && buffer[len(buffer)-3] == '-' && buffer[len(buffer)-2] == '0' {
+			buffer[len(buffer)-2] = buffer[len(buffer)-1]
+			buffer = buffer[:len(buffer)-1]
+		}
+	}
+
+	return buffer
+}
+
+const jsonHex = "0123456789abcdef"
+
+func appendJsonString(
+	buffer []byte,
+	value string,
+) []byte {
+	buffer = append(buffer, '"')
+	start := 0
+	for i := 0; i < len(value); {
+		if b := value[i]; b < utf8.RuneSelf {
+			if b >= ' ' && b != '"' && b != '\\' && b != '<' && b != '>' && b != '&' {
+				i++
+				continue
+			}
+
+			buffer = append(buffer, value[start:i]...)
+			switch b {
+			case '"', '\\':
+				buffer = append(buffer, '\\', b)
+			case '\n':
+				buffer = append(buffer, '\\', 'n')
+			case '\r':
+				buffer = append(buffer, '\\', 'r')
+			case '\t':
+				buffer = append(buffer, '\\', 't')
+			default:
+				buffer = append(buffer, '\\', 'u', '0', '0', jsonHex[b>>4], jsonHex[b&0xf])
+			}
+			i++
+			start = i
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(value[i:])
+		if r == utf8.RuneError && size == 1 {
+			buffer = append(buffer, value[start:i]...)
+			buffer = append(buffer, "\ufffd"...)
+			i += size
+			start = i
+			continue
+		}
+		if r == '\u2028' || r == '\u2029' {
+			buffer = append(buffer, value[start:i]...)
+			buffer = append(buffer, '\\', 'u', '2', '0', '2', jsonHex[r&0xf])
+			i += size
+			start = i
+			continue
+		}
+		i += size
+	}
+	buffer = append(buffer, value[start:]...)
 
-	return string(bytes)
+	return append(buffer, '"')
 }
@@ -17,7 +17,15 @@ func (jsonSerializerCreator) Accept(
 }
 
 func (jsonSerializerCreator) Create(
-	_ flam.Bag,
+	config flam.Bag,
 ) (Serializer, error) {
-	return newJsonSerializer(), nil
+	return newJsonSerializer(jsonSerializerOptions{
+		layout:       config.String("layout"),
+		timeField:    config.String("time_field"),
+		levelField:   config.String("level_field"),
+		messageField: config.String("message_field"),
+		channelField: config.String("channel_field"),
+		timeLayout:   config.String("time_layout"),
+		lowerLevel:   config.Bool("lower_level"),
+	})
 }
@@ -0,0 +1,139 @@
+package log
+
+import (
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	flam "github.com/happyhippyhippo/flam"
+)
+
+func Test_jsonSerializer(t *testing.T) {
+	timestamp := time.Date(2021, 1, 2, 3, 4, 5, 6000000, time.UTC)
+
+	t.Run("should serialize the entry fields in key order", func(t *testing.T) {
+		assert.Equal(
+			t,
+			`{"channel":"http","key":"value","level":"INFO","message":"message","time":"2021-01-02T03:04:05.006+0000"}`+"\n",
+			serialize(t, newDefaultJsonSerializer(t), Entry{
+				Timestamp: timestamp,
+				Level:     Info,
+				Channel:   "http",
+				Message:   "message",
+				Ctx:       flam.Bag{"key": "value"},
+			}))
+	})
+
+	t.Run("should not clobber the context keys with the reserved keys", func(t *testing.T) {
+		ctx := flam.Bag{"level": "user level", "channel": "user channel"}
+
+		assert.Equal(
+			t,
+			`{"channel":"http","ctx.channel":"user channel","ctx.level":"user level",`+
+				`"level":"INFO","message":"message","time":"2021-01-02T03:04:05.006+0000"}`+"\n",
+			serialize(t, newDefaultJsonSerializer(t), Entry{
+				Timestamp: timestamp,
+				Level:     Info,
+				Channel:   "http",
+				Message:   "message",
+				Ctx:       ctx,
+			}))
+		assert.Equal(t, flam.Bag{"level": "user level", "channel": "user channel"}, ctx)
+	})
+
+	t.Run("should not collide the renamed keys with literal context keys", func(t *testing.T) {
+		assert.Equal(
+			t,
+			`{"ctx.ctx.time":"user time","ctx.time":"literal","level":"INFO","message":"message",`+
+				`"time":"2021-01-02T03:04:05.006+0000"}`+"\n",
+			serialize(t, newDefaultJsonSerializer(t), Entry{
+				Timestamp: timestamp,
+				Level:     Info,
+				Message:   "message",
+				Ctx:       flam.Bag{"time": "user time", "ctx.time": "literal"},
+			}))
+	})
+
+	t.Run("should encode the values as encoding/json does", func(t *testing.T) {
+		values := flam.Bag{
+			"string":   "a\"b\\c\n\t<&>\u2028\x01\xff",
+			"float":    1.5e-7,
+			"big":      float32(3e21),
+			"int":      -12,
+			"uint":     uint8(7),
+			"bool":     true,
+			"nil":      nil,
+			"list":     []any{1, "two", flam.Bag{"b": 2, "a": 1}},
+			"duration": time.Second,
+			"time":     timestamp,
+			"struct":   struct{ Name string }{"name"},
+		}
+		expected, e := json.Marshal(map[string]any(values))
+		require.NoError(t, e)
+
+		assert.Equal(t, string(expected), string(appendJsonValue(nil, values)))
+	})
+
+	t.Run("should serialize the entry with the ecs layout", func(t *testing.T) {
+		serializer, e := newJsonSerializer(jsonSerializerOptions{layout: JsonLayoutEcs})
+		require.NoError(t, e)
+
+		assert.Equal(
+			t,
+			`{"@timestamp":"2021-01-02T03:04:05.006Z","ctx.message":"user message",`+
+				`"log.level":"warning","log.logger":"http","message":"message"}`+"\n",
+			serialize(t, serializer, Entry{
+				Timestamp: timestamp,
+				Level:     Warning,
+				Channel:   "http",
+				Message:   "message",
+				Ctx:       flam.Bag{"message": "user message"},
+			}))
+	})
+
+	t.Run("should serialize the entry with the configured fields", func(t *testing.T) {
+		serializer, e := newJsonSerializer(jsonSerializerOptions{
+			timeField:  "ts",
+			levelField: "severity",
+			timeLayout: time.RFC3339,
+			lowerLevel: true,
+		})
+		require.NoError(t, e)
+
+		assert.Equal(
+			t,
+			`{"ctx.ts":"user ts","message":"message","severity":"error","ts":"2021-01-02T03:04:05Z"}`+"\n",
+			serialize(t, serializer, Entry{
+				Timestamp: timestamp,
+				Level:     Error,
+				Message:   "message",
+				Ctx:       flam.Bag{"ts": "user ts"},
+			}))
+	})
+
+	t.Run("should return an error on unknown layout", func(t *testing.T) {
+		_, e := newJsonSerializer(jsonSerializerOptions{layout: "invalid"})
+
+		assert.ErrorIs(t, e, ErrInvalidSerializerFormat)
+	})
+}
+
+func Benchmark_jsonSerializer(b *testing.B) {
+	serializer, _ := newJsonSerializer(jsonSerializerOptions{})
+	target := newStream(Debug, []string{"*"}, serializer, io.Discard, false)
+	timestamp := time.Date(2021, 1, 2, 3, 4, 5, 6000000, time.UTC)
+	ctx := flam.Bag{
+		"request": flam.Bag{"id": 12, "path": "/users"},
+		"user":    "john",
+		"took":    1.5,
+	}
+
+	b.ReportAllocs()
+	for b.Loop() {
+		_ = target.Signal(timestamp, Info, "http", "message", ctx)
+	}
+}
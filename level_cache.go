@@ -0,0 +1,84 @@
+package log
+
+import (
+	"sync"
+)
+
+const maxCachedLevelChannels = 1024
+
+type filterableStream interface {
+	GetLevel() Level
+	acceptChannel(channel string) bool
+	observe(observer func())
+}
+
+type levelCache struct {
+	mutex      sync.Locker
+	generation uint64
+	levels     map[string]Level
+}
+
+func newLevelCache() *levelCache {
+	return &levelCache{
+		mutex:  &sync.Mutex{},
+		levels: map[string]Level{},
+	}
+}
+
+func (cache *levelCache) Get(
+	channel string,
+	compute func(channel string) Level,
+) Level {
+	cache.mutex.Lock()
+	level, ok := cache.levels[channel]
+	generation := cache.generation
+	cache.mutex.Unlock()
+
+	if ok {
+		return level
+	}
+
+	level = compute(channel)
+
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	if generation == cache.generation {
+		if len(cache.levels) >= maxCachedLevelChannels {
+			cache.levels = map[string]Level{}
+		}
+		cache.levels[channel] = level
+	}
+
+	return level
+}
+
+func (cache *levelCache) Invalidate() {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	cache.generation++
+	cache.levels = map[string]Level{}
+}
+
+func maxAcceptedLevel(
+	streams map[string]*managedStream,
+	channel string,
+) Level {
+	result := None
+	for _, managed := range streams {
+		filterable, ok := managed.stream.(filterableStream)
+		if !ok {
+			return Debug
+		}
+
+		level := filterable.GetLevel()
+		if level == None || (channel != "" && !filterable.acceptChannel(channel)) {
+			continue
+		}
+
+		result = max(result, level)
+	}
+
+	return result
+}
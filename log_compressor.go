@@ -0,0 +1,176 @@
+package log
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+
+	filesystem "github.com/happyhippyhippo/flam-filesystem"
+)
+
+var compressedExtensions = []string{".gz", ".zst"}
+
+type logCompressor struct {
+	disk      filesystem.Disk
+	extension string
+	encoder   func(writer io.Writer) (io.WriteCloser, error)
+	backlog   []string
+	wake      chan struct{}
+	pending   map[string]bool
+	mutex     sync.Locker
+	done      sync.WaitGroup
+	closed    bool
+}
+
+func newLogCompressor(
+	disk filesystem.Disk,
+	algorithm string,
+) (*logCompressor, error) {
+	compressor := &logCompressor{
+		disk:    disk,
+		wake:    make(chan struct{}, 1),
+		pending: map[string]bool{},
+		mutex:   &sync.Mutex{},
+	}
+
+	switch algorithm {
+	case CompressionGzip:
+		compressor.extension = ".gz"
+		compressor.encoder = func(writer io.Writer) (io.WriteCloser, error) {
+			return gzip.NewWriter(writer), nil
+		}
+	case CompressionZstd:
+		compressor.extension = ".zst"
+		compressor.encoder = func(writer io.Writer) (io.WriteCloser, error) {
+			return zstd.NewWriter(writer)
+		}
+	default:
+		return nil, newErrUnknownCompression(algorithm)
+	}
+
+	compressor.done.Add(1)
+	go compressor.run()
+
+	return compressor, nil
+}
+
+func (compressor *logCompressor) Queue(
+	path string,
+) {
+	compressor.mutex.Lock()
+	defer compressor.mutex.Unlock()
+
+	if compressor.closed || compressor.pending[path] {
+		return
+	}
+
+	compressor.backlog = append(compressor.backlog, path)
+	compressor.pending[path] = true
+	compressor.pending[path+compressor.extension] = true
+
+	select {
+	case compressor.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (compressor *logCompressor) IsPending(
+	path string,
+) bool {
+	compressor.mutex.Lock()
+	defer compressor.mutex.Unlock()
+
+	return compressor.pending[path]
+}
+
+func (compressor *logCompressor) Close() {
+	compressor.mutex.Lock()
+	if compressor.closed {
+		compressor.mutex.Unlock()
+		return
+	}
+	compressor.closed = true
+	close(compressor.wake)
+	compressor.mutex.Unlock()
+
+	compressor.done.Wait()
+}
+
+func (compressor *logCompressor) run() {
+	defer compressor.done.Done()
+
+	for {
+		compressor.mutex.Lock()
+		if len(compressor.backlog) == 0 {
+			closed := compressor.closed
+			compressor.mutex.Unlock()
+			if closed {
+				return
+			}
+			<-compressor.wake
+			continue
+		}
+		path := compressor.backlog[0]
+		compressor.backlog = compressor.backlog[1:]
+		compressor.mutex.Unlock()
+
+		_ = compressor.compress(path)
+
+		compressor.mutex.Lock()
+		delete(compressor.pending, path)
+		delete(compressor.pending, path+compressor.extension)
+		compressor.mutex.Unlock()
+	}
+}
+
+func (compressor *logCompressor) compress(
+	path string,
+) error {
+	source, e := compressor.disk.Open(path)
+	if e != nil {
+		return e
+	}
+
+	target := path + compressor.extension
+	file, e := compressor.disk.OpenFile(target, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+	if e != nil {
+		_ = source.Close()
+		return e
+	}
+
+	e = compressor.write(source, file)
+	_ = source.Close()
+	if e != nil {
+		_ = compressor.disk.Remove(target)
+		return e
+	}
+
+	return compressor.disk.Remove(path)
+}
+
+func (compressor *logCompressor) write(
+	source io.Reader,
+	file filesystem.File,
+) error {
+	encoder, e := compressor.encoder(file)
+	if e != nil {
+		_ = file.Close()
+		return e
+	}
+
+	if _, e := io.Copy(encoder, source); e != nil {
+		_ = encoder.Close()
+		_ = file.Close()
+		return e
+	}
+
+	if e := encoder.Close(); e != nil {
+		_ = file.Close()
+		return e
+	}
+
+	return file.Close()
+}
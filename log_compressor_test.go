@@ -0,0 +1,64 @@
+package log
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_logCompressor(t *testing.T) {
+	t.Run("should compress every queued file regardless of the backlog size", func(t *testing.T) {
+		disk := afero.NewMemMapFs()
+		for i := range 200 {
+			require.NoError(t, afero.WriteFile(disk, fmt.Sprintf("/file-%d", i), []byte("message"), 0o644))
+		}
+
+		compressor, e := newLogCompressor(disk, CompressionGzip)
+		require.NoError(t, e)
+
+		for i := range 200 {
+			compressor.Queue(fmt.Sprintf("/file-%d", i))
+		}
+		compressor.Close()
+
+		for i := range 200 {
+			found, _ := afero.Exists(disk, fmt.Sprintf("/file-%d", i))
+			assert.False(t, found)
+			found, _ = afero.Exists(disk, fmt.Sprintf("/file-%d.gz", i))
+			assert.True(t, found)
+		}
+	})
+
+	t.Run("should ignore the files queued after closing", func(t *testing.T) {
+		disk := afero.NewMemMapFs()
+		require.NoError(t, afero.WriteFile(disk, "/file", []byte("message"), 0o644))
+
+		compressor, e := newLogCompressor(disk, CompressionGzip)
+		require.NoError(t, e)
+		compressor.Close()
+		compressor.Queue("/file")
+
+		found, _ := afero.Exists(disk, "/file")
+		assert.True(t, found)
+	})
+	t.Run("should not overwrite an existing archive", func(t *testing.T) {
+		disk := afero.NewMemMapFs()
+		require.NoError(t, afero.WriteFile(disk, "/file", []byte("message"), 0o644))
+		require.NoError(t, afero.WriteFile(disk, "/file.gz", []byte("archive"), 0o644))
+
+		compressor, e := newLogCompressor(disk, CompressionGzip)
+		require.NoError(t, e)
+		compressor.Queue("/file")
+		compressor.Close()
+
+		data, e := afero.ReadFile(disk, "/file")
+		require.NoError(t, e)
+		assert.Equal(t, "message", string(data))
+		data, e = afero.ReadFile(disk, "/file.gz")
+		require.NoError(t, e)
+		assert.Equal(t, "archive", string(data))
+	})
+}
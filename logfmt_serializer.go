@@ -0,0 +1,115 @@
+package log
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	flam "github.com/happyhippyhippo/flam"
+)
+
+type logfmtSerializer struct{}
+
+func newLogfmtSerializer() Serializer {
+	return &logfmtSerializer{}
+}
+
+func (logfmtSerializer) Close() error {
+	return nil
+}
+
+func (serializer logfmtSerializer) Serialize(
+	writer io.Writer,
+	entry Entry,
+) error {
+	output := &serializationWriter{writer: writer}
+	_, _ = output.WriteString("time=")
+	_, _ = output.WriteString(entry.Timestamp.Format("2006-01-02T15:04:05.000-0700"))
+	_, _ = output.WriteString(" level=")
+	_, _ = output.WriteString(LevelName[entry.Level])
+
+	if entry.Channel != "" {
+		_, _ = output.WriteString(" channel=")
+		serializer.writeValue(output, entry.Channel)
+	}
+
+	_, _ = output.WriteString(" msg=")
+	serializer.writeValue(output, entry.Message)
+
+	serializer.writeBag(output, entry.Ctx, func(key string) string {
+		return renameContextKey(key, entry.Ctx, isReservedKey)
+	})
+	_ = output.WriteByte('\n')
+
+	return output.e
+}
+
+func (serializer logfmtSerializer) writeBag(
+	writer *serializationWriter,
+	bag flam.Bag,
+	rename func(key string) string,
+) {
+	walkContext("", bag, func(prefix, key string) string {
+		key = serializer.key(key)
+		switch {
+		case prefix != "":
+			return prefix + "." + key
+		case rename != nil:
+			return rename(key)
+		default:
+			return key
+		}
+	}, func(path string, value any) {
+		_ = writer.WriteByte(' ')
+		_, _ = writer.WriteString(path)
+		_ = writer.WriteByte('=')
+		serializer.writeValue(writer, serializer.format(value))
+	})
+}
+
+func (logfmtSerializer) key(
+	key string,
+) string {
+	return strings.Map(func(r rune) rune {
+		if r <= ' ' || r == '=' || r == '"' || r == utf8.RuneError {
+			return '_'
+		}
+		return r
+	}, key)
+}
+
+func (logfmtSerializer) format(
+	value any,
+) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return v
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case error:
+		return v.Error()
+	case fmt.Stringer:
+		return v.String()
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func (logfmtSerializer) writeValue(
+	writer *serializationWriter,
+	value string,
+) {
+	if value != "" && !strings.ContainsFunc(value, func(r rune) bool {
+		return r <= ' ' || r == '=' || r == '"' || r == '\\' || r == utf8.RuneError
+	}) {
+		_, _ = writer.WriteString(value)
+		return
+	}
+
+	_, _ = writer.WriteString(strconv.Quote(value))
+}
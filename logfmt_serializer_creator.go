@@ -0,0 +1,23 @@
+package log
+
+import (
+	flam "github.com/happyhippyhippo/flam"
+)
+
+type logfmtSerializerCreator struct{}
+
+func newLogfmtSerializerCreator() SerializerCreator {
+	return &logfmtSerializerCreator{}
+}
+
+func (logfmtSerializerCreator) Accept(
+	config flam.Bag,
+) bool {
+	return config.String("driver") == SerializerDriverLogfmt
+}
+
+func (logfmtSerializerCreator) Create(
+	_ flam.Bag,
+) (Serializer, error) {
+	return newLogfmtSerializer(), nil
+}
@@ -0,0 +1,103 @@
+package log
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	flam "github.com/happyhippyhippo/flam"
+)
+
+func Test_logfmtSerializer(t *testing.T) {
+	getSerializer := func(t *testing.T) Serializer {
+		serializer, e := getTestSerializer(t, "logfmt", flam.Bag{"driver": SerializerDriverLogfmt})
+		require.NoError(t, e)
+
+		return serializer
+	}
+
+	timestamp := time.Date(2021, 1, 2, 3, 4, 5, 6000000, time.UTC)
+
+	t.Run("should serialize the entry base fields", func(t *testing.T) {
+		serializer := getSerializer(t)
+
+		assert.Equal(
+			t,
+			"time=2021-01-02T03:04:05.006+0000 level=info msg=message\n",
+			serialize(t, serializer, Entry{Timestamp: timestamp, Level: Info, Message: "message", Ctx: flam.Bag{}}))
+	})
+
+	t.Run("should serialize the channel before the message", func(t *testing.T) {
+		serializer := getSerializer(t)
+
+		assert.Equal(
+			t,
+			"time=2021-01-02T03:04:05.006+0000 level=error channel=http msg=message\n",
+			serialize(t, serializer, Entry{Timestamp: timestamp, Level: Error, Message: "message", Channel: "http", Ctx: flam.Bag{}}))
+	})
+
+	t.Run("should quote and escape the values when needed", func(t *testing.T) {
+		serializer := getSerializer(t)
+
+		assert.Equal(
+			t,
+			`time=2021-01-02T03:04:05.006+0000 level=debug msg="a \"quoted\" message" `+
+				`empty="" equal="a=b" error="some error" flag=true line="first\nsecond" number=12`+"\n",
+			serialize(t, serializer, Entry{Timestamp: timestamp, Level: Debug, Message: `a "quoted" message`, Ctx: flam.Bag{
+				"number": 12,
+				"flag":   true,
+				"empty":  "",
+				"equal":  "a=b",
+				"line":   "first\nsecond",
+				"error":  errors.New("some error"),
+			}}))
+	})
+
+	t.Run("should flatten the nested bags into dotted keys", func(t *testing.T) {
+		serializer := getSerializer(t)
+
+		assert.Equal(
+			t,
+			"time=2021-01-02T03:04:05.006+0000 level=info msg=message "+
+				"request.id=123 request.user.name=john zone=eu\n",
+			serialize(t, serializer, Entry{Timestamp: timestamp, Level: Info, Message: "message", Ctx: flam.Bag{
+				"zone": "eu",
+				"request": flam.Bag{
+					"id": 123,
+					"user": flam.Bag{
+						"name": "john",
+					},
+				},
+			}}))
+	})
+
+	t.Run("should not clobber the context keys with the reserved keys", func(t *testing.T) {
+		ctx := flam.Bag{"msg": "user message"}
+
+		assert.Equal(
+			t,
+			"time=2021-01-02T03:04:05.006+0000 level=info msg=message ctx.msg=\"user message\"\n",
+			serialize(t, newLogfmtSerializer(), Entry{
+				Timestamp: timestamp,
+				Level:     Info,
+				Message:   "message",
+				Ctx:       ctx,
+			}))
+		assert.Equal(t, flam.Bag{"msg": "user message"}, ctx)
+	})
+
+	t.Run("should not collide the renamed keys with literal context keys", func(t *testing.T) {
+		assert.Equal(
+			t,
+			"time=2021-01-02T03:04:05.006+0000 level=info msg=message ctx.msg=literal ctx.ctx.msg=\"user message\"\n",
+			serialize(t, newLogfmtSerializer(), Entry{
+				Timestamp: timestamp,
+				Level:     Info,
+				Message:   "message",
+				Ctx:       flam.Bag{"msg": "user message", "ctx.msg": "literal"},
+			}))
+	})
+}
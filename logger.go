@@ -0,0 +1,216 @@
+package log
+
+import (
+	"context"
+
+	flam "github.com/happyhippyhippo/flam"
+)
+
+type Logger struct {
+	manager *manager
+	channel string
+	ctx     flam.Bag
+}
+
+func newLogger(
+	manager *manager,
+	channel string,
+) Logger {
+	return Logger{
+		manager: manager,
+		channel: channel,
+		ctx:     flam.Bag{},
+	}
+}
+
+func (logger Logger) With(
+	ctx flam.Bag,
+) Logger {
+	merged := flam.Bag{}
+	if logger.ctx != nil {
+		merged = logger.ctx.Clone()
+	}
+	merged.Merge(ctx.Clone())
+	logger.ctx = merged
+
+	return logger
+}
+
+func (logger Logger) IsEnabled(
+	level Level,
+) bool {
+	if logger.manager == nil {
+		return false
+	}
+
+	return logger.manager.IsEnabled(level, logger.channel)
+}
+
+func (logger Logger) Signal(
+	level Level,
+	message string,
+	ctx ...flam.Bag,
+) error {
+	if logger.manager == nil {
+		return nil
+	}
+
+	return logger.manager.Signal(level, logger.channel, message, logger.merge(ctx)...)
+}
+
+func (logger Logger) Fatal(
+	message string,
+	ctx ...flam.Bag,
+) error {
+	if logger.manager == nil {
+		return nil
+	}
+
+	return logger.manager.Signal(Fatal, logger.channel, message, logger.merge(ctx)...)
+}
+
+func (logger Logger) Error(
+	message string,
+	ctx ...flam.Bag,
+) error {
+	if logger.manager == nil {
+		return nil
+	}
+
+	return logger.manager.Signal(Error, logger.channel, message, logger.merge(ctx)...)
+}
+
+func (logger Logger) Warning(
+	message string,
+	ctx ...flam.Bag,
+) error {
+	if logger.manager == nil {
+		return nil
+	}
+
+	return logger.manager.Signal(Warning, logger.channel, message, logger.merge(ctx)...)
+}
+
+func (logger Logger) Notice(
+	message string,
+	ctx ...flam.Bag,
+) error {
+	if logger.manager == nil {
+		return nil
+	}
+
+	return logger.manager.Signal(Notice, logger.channel, message, logger.merge(ctx)...)
+}
+
+func (logger Logger) Info(
+	message string,
+	ctx ...flam.Bag,
+) error {
+	if logger.manager == nil {
+		return nil
+	}
+
+	return logger.manager.Signal(Info, logger.channel, message, logger.merge(ctx)...)
+}
+
+func (logger Logger) Debug(
+	message string,
+	ctx ...flam.Bag,
+) error {
+	if logger.manager == nil {
+		return nil
+	}
+
+	return logger.manager.Signal(Debug, logger.channel, message, logger.merge(ctx)...)
+}
+
+func (logger Logger) SignalCtx(
+	ctx context.Context,
+	level Level,
+	message string,
+	bag ...flam.Bag,
+) error {
+	if logger.manager == nil {
+		return nil
+	}
+
+	return logger.manager.SignalCtx(ctx, level, logger.channel, message, logger.merge(bag)...)
+}
+
+func (logger Logger) FatalCtx(
+	ctx context.Context,
+	message string,
+	bag ...flam.Bag,
+) error {
+	if logger.manager == nil {
+		return nil
+	}
+
+	return logger.manager.SignalCtx(ctx, Fatal, logger.channel, message, logger.merge(bag)...)
+}
+
+func (logger Logger) ErrorCtx(
+	ctx context.Context,
+	message string,
+	bag ...flam.Bag,
+) error {
+	if logger.manager == nil {
+		return nil
+	}
+
+	return logger.manager.SignalCtx(ctx, Error, logger.channel, message, logger.merge(bag)...)
+}
+
+func (logger Logger) WarningCtx(
+	ctx context.Context,
+	message string,
+	bag ...flam.Bag,
+) error {
+	if logger.manager == nil {
+		return nil
+	}
+
+	return logger.manager.SignalCtx(ctx, Warning, logger.channel, message, logger.merge(bag)...)
+}
+
+func (logger Logger) NoticeCtx(
+	ctx context.Context,
+	message string,
+	bag ...flam.Bag,
+) error {
+	if logger.manager == nil {
+		return nil
+	}
+
+	return logger.manager.SignalCtx(ctx, Notice, logger.channel, message, logger.merge(bag)...)
+}
+
+func (logger Logger) InfoCtx(
+	ctx context.Context,
+	message string,
+	bag ...flam.Bag,
+) error {
+	if logger.manager == nil {
+		return nil
+	}
+
+	return logger.manager.SignalCtx(ctx, Info, logger.channel, message, logger.merge(bag)...)
+}
+
+func (logger Logger) DebugCtx(
+	ctx context.Context,
+	message string,
+	bag ...flam.Bag,
+) error {
+	if logger.manager == nil {
+		return nil
+	}
+
+	return logger.manager.SignalCtx(ctx, Debug, logger.channel, message, logger.merge(bag)...)
+}
+
+func (logger Logger) merge(
+	ctx []flam.Bag,
+) []flam.Bag {
+	return append([]flam.Bag{logger.ctx}, ctx...)
+}
@@ -0,0 +1,146 @@
+package log
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	flam "github.com/happyhippyhippo/flam"
+)
+
+func Test_Logger(t *testing.T) {
+	setup := func(t *testing.T, stream Stream) Facade {
+		container := newTestContainer(t, flam.Bag{})
+
+		var facade Facade
+		require.NoError(t, container.Invoke(func(f Facade) { facade = f }))
+		if stream != nil {
+			require.NoError(t, facade.AddStream("stream", stream))
+		}
+
+		return facade
+	}
+
+	t.Run("should not be enabled if there are no streams", func(t *testing.T) {
+		facade := setup(t, nil)
+
+		assert.False(t, facade.Channel("http").IsEnabled(Fatal))
+	})
+
+	t.Run("should signal the entries on the bound channel", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		stream := NewStreamMock(ctrl)
+		for _, level := range []Level{Fatal, Error, Warning, Notice, Info, Debug} {
+			stream.EXPECT().Signal(gomock.Any(), level, "http", "message", flam.Bag{}).Return(nil).Times(1)
+		}
+
+		facade := setup(t, stream)
+		logger := facade.Channel("http")
+
+		assert.True(t, logger.IsEnabled(Debug))
+		assert.NoError(t, logger.Fatal("message"))
+		assert.NoError(t, logger.Error("message"))
+		assert.NoError(t, logger.Warning("message"))
+		assert.NoError(t, logger.Notice("message"))
+		assert.NoError(t, logger.Info("message"))
+		assert.NoError(t, logger.Signal(Debug, "message"))
+		assert.NoError(t, facade.Flush())
+	})
+
+	t.Run("should broadcast the entries when bound to no channel", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		stream := NewStreamMock(ctrl)
+		stream.EXPECT().Broadcast(gomock.Any(), Debug, "message", flam.Bag{}).Return(nil).Times(1)
+
+		facade := setup(t, stream)
+
+		assert.NoError(t, facade.Channel("").Debug("message"))
+		assert.NoError(t, facade.Flush())
+	})
+
+	t.Run("should merge the bound context before the call site context", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		stream := NewStreamMock(ctrl)
+		stream.EXPECT().Signal(gomock.Any(), Info, "http", "message", flam.Bag{
+			"request": flam.Bag{"id": 1, "path": "/"},
+			"user":    2,
+		}).Return(nil).Times(1)
+
+		facade := setup(t, stream)
+		logger := facade.Channel("http").
+			With(flam.Bag{"request": flam.Bag{"id": 1}}).
+			With(flam.Bag{"user": 1})
+
+		assert.NoError(t, logger.Info("message", flam.Bag{"request": flam.Bag{"path": "/"}, "user": 2}))
+		assert.NoError(t, facade.Flush())
+	})
+
+	t.Run("should not share the context between derived loggers", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		stream := NewStreamMock(ctrl)
+		gomock.InOrder(
+			stream.EXPECT().Signal(gomock.Any(), Info, "http", "parent", flam.Bag{"request": flam.Bag{"id": 1}}).Return(nil),
+			stream.EXPECT().Signal(gomock.Any(), Info, "http", "child", flam.Bag{"request": flam.Bag{"id": 1, "user": 2}}).Return(nil),
+		)
+
+		facade := setup(t, stream)
+		ctx := flam.Bag{"request": flam.Bag{"id": 1}}
+		parent := facade.Channel("http").With(ctx)
+		child := parent.With(flam.Bag{"request": flam.Bag{"user": 2}})
+		_ = ctx.Set("request.id", 3)
+
+		assert.NoError(t, parent.Info("parent"))
+		assert.NoError(t, child.Info("child"))
+		assert.NoError(t, facade.Flush())
+	})
+
+	t.Run("should be safe to use concurrently", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		stream := NewStreamMock(ctrl)
+		stream.EXPECT().Signal(gomock.Any(), Info, "http", "message", gomock.Any()).Return(nil).Times(10)
+
+		facade := setup(t, stream)
+		logger := facade.Channel("http").With(flam.Bag{"request": flam.Bag{"id": 1}})
+
+		wg := sync.WaitGroup{}
+		for i := range 10 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				assert.NoError(t, logger.With(flam.Bag{"request": flam.Bag{"worker": i}}).Info("message"))
+			}()
+		}
+		wg.Wait()
+
+		assert.NoError(t, facade.Flush())
+	})
+
+	t.Run("should ignore the calls on a zero value logger", func(t *testing.T) {
+		logger := Logger{}.With(flam.Bag{"request": flam.Bag{"id": 1}})
+
+		assert.False(t, logger.IsEnabled(Fatal))
+		assert.NoError(t, logger.Signal(Info, "message"))
+		assert.NoError(t, logger.Fatal("message"))
+		assert.NoError(t, logger.Error("message"))
+		assert.NoError(t, logger.Warning("message"))
+		assert.NoError(t, logger.Notice("message"))
+		assert.NoError(t, logger.Info("message"))
+		assert.NoError(t, logger.Debug("message"))
+		assert.NoError(t, logger.SignalCtx(context.Background(), Info, "message"))
+		assert.NoError(t, logger.InfoCtx(context.Background(), "message"))
+	})
+}
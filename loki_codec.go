@@ -0,0 +1,242 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/klauspost/compress/snappy"
+)
+
+type lokiStream struct {
+	key    string
+	labels map[string]string
+	items  []httpBatchItem
+}
+
+type lokiCodec struct {
+	format    string
+	labels    map[string]string
+	labelKeys []string
+}
+
+func newLokiCodec(
+	format string,
+	labels map[string]string,
+	labelKeys []string,
+) (httpBatchCodec, error) {
+	switch format {
+	case "":
+		format = LokiFormatProtobuf
+	case LokiFormatProtobuf, LokiFormatJson:
+	default:
+		return nil, newErrUnknownLokiFormat(format)
+	}
+
+	return &lokiCodec{
+		format:    format,
+		labels:    labels,
+		labelKeys: labelKeys,
+	}, nil
+}
+
+func (codec lokiCodec) Encode(
+	items []httpBatchItem,
+) (string, []byte, error) {
+	streams := codec.group(items)
+	if codec.format == LokiFormatJson {
+		return codec.json(streams)
+	}
+
+	return codec.protobuf(streams)
+}
+
+func (codec lokiCodec) Decode(
+	items []httpBatchItem,
+	status int,
+	body []byte,
+) ([]httpBatchItem, int, error) {
+	switch {
+	case status >= http.StatusOK && status < http.StatusMultipleChoices:
+		return nil, 0, nil
+	case status == http.StatusBadRequest &&
+		(bytes.Contains(body, []byte("out of order")) || bytes.Contains(body, []byte("too far behind"))):
+		return nil, codec.ignored(items, body), nil
+	default:
+		return nil, len(items), newErrHttpStatus(status)
+	}
+}
+
+func (lokiCodec) ignored(
+	items []httpBatchItem,
+	body []byte,
+) int {
+	_, total, found := bytes.Cut(body, []byte("total ignored: "))
+	if !found {
+		return len(items)
+	}
+
+	fields := strings.Fields(string(total))
+	if len(fields) == 0 {
+		return len(items)
+	}
+
+	ignored, e := strconv.Atoi(fields[0])
+	if e != nil || ignored < 0 || ignored > len(items) {
+		return len(items)
+	}
+
+	return ignored
+}
+
+func (codec lokiCodec) group(
+	items []httpBatchItem,
+) []*lokiStream {
+	index := map[string]*lokiStream{}
+	var streams []*lokiStream
+	for _, item := range items {
+		labels := codec.labelsOf(item.entry)
+		key := codec.key(labels)
+
+		stream, ok := index[key]
+		if !ok {
+			stream = &lokiStream{key: key, labels: labels}
+			index[key] = stream
+			streams = append(streams, stream)
+		}
+		stream.items = append(stream.items, item)
+	}
+
+	slices.SortFunc(streams, func(a, b *lokiStream) int {
+		return strings.Compare(a.key, b.key)
+	})
+	for _, stream := range streams {
+		slices.SortStableFunc(stream.items, func(a, b httpBatchItem) int {
+			return a.entry.Timestamp.Compare(b.entry.Timestamp)
+		})
+	}
+
+	return streams
+}
+
+func (codec lokiCodec) labelsOf(
+	entry Entry,
+) map[string]string {
+	labels := map[string]string{}
+	for name, value := range codec.labels {
+		labels[codec.labelName(name)] = value
+	}
+
+	labels["level"] = LevelName[entry.Level]
+	if entry.Channel != "" {
+		labels["channel"] = entry.Channel
+	}
+
+	for _, key := range codec.labelKeys {
+		if value := entry.Ctx.Get(key); value != nil {
+			labels[codec.labelName(key)] = logfmtSerializer{}.format(value)
+		}
+	}
+
+	return labels
+}
+
+func (lokiCodec) labelName(
+	name string,
+) string {
+	name = strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '_' {
+			return r
+		}
+		return '_'
+	}, name)
+
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "_" + name
+	}
+
+	return name
+}
+
+func (lokiCodec) key(
+	labels map[string]string,
+) string {
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	key := &strings.Builder{}
+	_ = key.WriteByte('{')
+	for i, name := range names {
+		if i != 0 {
+			_, _ = key.WriteString(", ")
+		}
+		_, _ = key.WriteString(name)
+		_ = key.WriteByte('=')
+		_, _ = key.WriteString(strconv.Quote(labels[name]))
+	}
+	_ = key.WriteByte('}')
+
+	return key.String()
+}
+
+func (lokiCodec) line(
+	item httpBatchItem,
+) string {
+	return string(bytes.TrimRight(item.payload, "\n"))
+}
+
+func (codec lokiCodec) json(
+	streams []*lokiStream,
+) (string, []byte, error) {
+	type jsonStream struct {
+		Stream map[string]string `json:"stream"`
+		Values [][2]string       `json:"values"`
+	}
+
+	request := struct {
+		Streams []jsonStream `json:"streams"`
+	}{}
+	for _, stream := range streams {
+		values := make([][2]string, 0, len(stream.items))
+		for _, item := range stream.items {
+			values = append(values, [2]string{
+				strconv.FormatInt(item.entry.Timestamp.UnixNano(), 10),
+				codec.line(item),
+			})
+		}
+		request.Streams = append(request.Streams, jsonStream{Stream: stream.labels, Values: values})
+	}
+
+	body, e := json.Marshal(request)
+	if e != nil {
+		return "", nil, e
+	}
+
+	return "application/json", body, nil
+}
+
+func (codec lokiCodec) protobuf(
+	streams []*lokiStream,
+) (string, []byte, error) {
+	var request protoBuffer
+	for _, stream := range streams {
+		message := protoBuffer{}.String(1, stream.key)
+		for _, item := range stream.items {
+			timestamp := protoBuffer{}.
+				Int(1, item.entry.Timestamp.Unix()).
+				Int(2, int64(item.entry.Timestamp.Nanosecond()))
+			message = message.Message(2, protoBuffer{}.
+				Message(1, timestamp).
+				String(2, codec.line(item)))
+		}
+		request = request.Message(1, message)
+	}
+
+	return "application/x-protobuf", snappy.Encode(nil, request), nil
+}
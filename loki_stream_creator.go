@@ -0,0 +1,75 @@
+package log
+
+import (
+	"sort"
+
+	flam "github.com/happyhippyhippo/flam"
+)
+
+type lokiStreamCreator struct {
+	streamCreator
+}
+
+func newLokiStreamCreator(
+	serializerFactory serializerFactory,
+) StreamCreator {
+	return &lokiStreamCreator{
+		streamCreator: streamCreator{
+			serializerFactory: serializerFactory,
+		},
+	}
+}
+
+func (lokiStreamCreator) Accept(
+	config flam.Bag,
+) bool {
+	return config.String("driver") == StreamDriverLoki &&
+		config.Has("url")
+}
+
+func (creator lokiStreamCreator) Create(
+	config flam.Bag,
+) (Stream, error) {
+	var serializer Serializer
+	if serializerId := config.String("serializer", DefaultSerializer); serializerId != "" {
+		var e error
+		if serializer, e = creator.serializerFactory.Get(serializerId); e != nil {
+			return nil, e
+		}
+	}
+
+	labels := map[string]string{}
+	for name, value := range config.Bag("labels") {
+		if typedValue, ok := value.(string); ok {
+			labels[name] = typedValue
+		}
+	}
+
+	codec, e := newLokiCodec(
+		config.String("format"),
+		labels,
+		creator.getChannels(config.Slice("label_keys")))
+	if e != nil {
+		return nil, e
+	}
+
+	options := httpBatchWriterOptionsFrom(config)
+	if tenant := config.String("tenant"); tenant != "" {
+		options.headers["X-Scope-OrgID"] = tenant
+	}
+
+	writer, e := newHttpBatchWriter(codec, options)
+	if e != nil {
+		return nil, e
+	}
+
+	channels := creator.getChannels(config.Slice("channels"))
+	sort.Strings(channels)
+
+	return newStream(
+		LevelFrom(config.Get("level"), DefaultLevel),
+		channels,
+		serializer,
+		writer,
+		true), nil
+}
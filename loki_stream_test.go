@@ -0,0 +1,149 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/klauspost/compress/snappy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	flam "github.com/happyhippyhippo/flam"
+)
+
+func Test_lokiStream(t *testing.T) {
+	timestamp := time.Date(2021, 1, 2, 3, 4, 5, 6000000, time.UTC)
+
+	t.Run("should return an error on unknown format", func(t *testing.T) {
+		_, e := getDriverTestStream(t, StreamDriverLoki, flam.Bag{"url": "http://localhost", "format": "invalid"})
+
+		assert.ErrorIs(t, e, ErrUnknownLokiFormat)
+	})
+
+	t.Run("should push the entries grouped by label set in json", func(t *testing.T) {
+		recorder := &httpRecorder{}
+		server := httptest.NewServer(recorder)
+		defer server.Close()
+
+		stream, e := getDriverTestStream(t, StreamDriverLoki, flam.Bag{
+			"url":        server.URL,
+			"format":     LokiFormatJson,
+			"tenant":     "tenant",
+			"labels":     flam.Bag{"job": "app"},
+			"label_keys": []any{"request.zone"},
+			"batch_size": 3,
+		})
+		require.NoError(t, e)
+		defer func() { _ = stream.Close() }()
+
+		require.NoError(t, stream.Signal(timestamp.Add(time.Second), Info, "http", "message2", flam.Bag{}))
+		require.NoError(t, stream.Signal(timestamp, Info, "http", "message1", flam.Bag{}))
+		require.NoError(t, stream.Broadcast(timestamp, Error, "message3", flam.Bag{
+			"request": flam.Bag{"zone": "eu"},
+		}))
+
+		require.Len(t, recorder.Await(t, 1), 1)
+		assert.Equal(t, "application/json", recorder.requests[0].Header.Get("Content-Type"))
+		assert.Equal(t, "tenant", recorder.requests[0].Header.Get("X-Scope-OrgID"))
+
+		var request map[string]any
+		require.NoError(t, json.Unmarshal([]byte(recorder.Bodies()[0]), &request))
+		assert.Equal(t, map[string]any{"streams": []any{
+			map[string]any{
+				"stream": map[string]any{"channel": "http", "level": "info", "job": "app"},
+				"values": []any{
+					[]any{"1609556645006000000", "message1"},
+					[]any{"1609556646006000000", "message2"},
+				},
+			},
+			map[string]any{
+				"stream": map[string]any{"level": "error", "job": "app", "request_zone": "eu"},
+				"values": []any{
+					[]any{"1609556645006000000", "message3"},
+				},
+			},
+		}}, request)
+	})
+
+	t.Run("should push the entries in snappy compressed protobuf", func(t *testing.T) {
+		recorder := &httpRecorder{}
+		server := httptest.NewServer(recorder)
+		defer server.Close()
+
+		stream, e := getDriverTestStream(t, StreamDriverLoki, flam.Bag{
+			"url":        server.URL,
+			"batch_size": 2,
+		})
+		require.NoError(t, e)
+		defer func() { _ = stream.Close() }()
+
+		require.NoError(t, stream.Signal(timestamp.Add(time.Second), Info, "http", "message2", flam.Bag{}))
+		require.NoError(t, stream.Signal(timestamp, Info, "http", "message1", flam.Bag{}))
+
+		require.Len(t, recorder.Await(t, 1), 1)
+		assert.Equal(t, "application/x-protobuf", recorder.requests[0].Header.Get("Content-Type"))
+
+		body, e := snappy.Decode(nil, []byte(recorder.Bodies()[0]))
+		require.NoError(t, e)
+
+		assert.Contains(t, string(body), `{channel="http", level="info"}`)
+		assert.Less(t, bytes.Index(body, []byte("message1")), bytes.Index(body, []byte("message2")))
+	})
+
+	t.Run("should count the entries loki rejects as out of order as dropped", func(t *testing.T) {
+		recorder := &httpRecorder{
+			statuses: []int{http.StatusBadRequest, http.StatusBadRequest},
+			replies: []string{
+				"entry with timestamp 2021-01-02 ignored, reason: 'entry out of order', total ignored: 1 out of 2",
+				"invalid labels",
+			},
+		}
+		server := httptest.NewServer(recorder)
+		defer server.Close()
+
+		stream, e := getDriverTestStream(t, StreamDriverLoki, flam.Bag{
+			"url":        server.URL,
+			"batch_size": 2,
+		})
+		require.NoError(t, e)
+		defer func() { _ = stream.Close() }()
+		reports := reportsOf(stream)
+
+		require.NoError(t, stream.Broadcast(timestamp, Info, "message1", flam.Bag{}))
+		require.NoError(t, stream.Broadcast(timestamp, Info, "message2", flam.Bag{}))
+		assert.Equal(t, testReport{dropped: 1}, nextReport(t, reports))
+
+		require.NoError(t, stream.Broadcast(timestamp, Info, "message3", flam.Bag{}))
+		require.NoError(t, stream.Broadcast(timestamp, Info, "message4", flam.Bag{}))
+		report := nextReport(t, reports)
+		assert.Equal(t, 2, report.dropped)
+		assert.ErrorIs(t, report.e, ErrHttpStatus)
+		assert.Len(t, recorder.Bodies(), 2)
+	})
+
+	t.Run("should count the whole batch as dropped when loki does not report the ignored total", func(t *testing.T) {
+		recorder := &httpRecorder{
+			statuses: []int{http.StatusBadRequest},
+			replies:  []string{"entry too far behind"},
+		}
+		server := httptest.NewServer(recorder)
+		defer server.Close()
+
+		stream, e := getDriverTestStream(t, StreamDriverLoki, flam.Bag{
+			"url":        server.URL,
+			"batch_size": 2,
+		})
+		require.NoError(t, e)
+		defer func() { _ = stream.Close() }()
+		reports := reportsOf(stream)
+
+		require.NoError(t, stream.Broadcast(timestamp, Info, "message1", flam.Bag{}))
+		require.NoError(t, stream.Broadcast(timestamp, Info, "message2", flam.Bag{}))
+
+		assert.Equal(t, testReport{dropped: 2}, nextReport(t, reports))
+	})
+}
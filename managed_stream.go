@@ -0,0 +1,141 @@
+package log
+
+import (
+	"sync"
+	"time"
+)
+
+const minRetryInterval = 10 * time.Millisecond
+
+type retryPolicy struct {
+	backoff    time.Duration
+	maxBackoff time.Duration
+	maxPending int
+}
+
+type managedStream struct {
+	mutex    sync.Locker
+	stream   Stream
+	pending  []Entry
+	failures int
+	retryAt  time.Time
+	err      error
+	worker   *streamWorker
+}
+
+func newManagedStream(
+	stream Stream,
+) *managedStream {
+	return &managedStream{
+		mutex:  &sync.Mutex{},
+		stream: stream,
+	}
+}
+
+func (managed *managedStream) Deliver(
+	entries []Entry,
+	now time.Time,
+	policy retryPolicy,
+) (int, error) {
+	managed.mutex.Lock()
+	defer managed.mutex.Unlock()
+
+	backlog := entries
+	if len(managed.pending) != 0 {
+		backlog = append(managed.pending, entries...)
+	}
+
+	if managed.failures != 0 && now.Before(managed.retryAt) {
+		dropped := managed.retain(backlog, policy)
+		return dropped, newErrStreamBackoff(len(managed.pending), managed.err)
+	}
+
+	for i, entry := range backlog {
+		if e := deliverEntry(managed.stream, entry); e != nil {
+			managed.failures++
+			managed.retryAt = now.Add(policy.delay(managed.failures))
+			managed.err = e
+			return managed.retain(backlog[i:], policy), e
+		}
+	}
+
+	managed.pending = nil
+	managed.failures = 0
+	managed.err = nil
+
+	return 0, nil
+}
+
+func (managed *managedStream) Expedite() {
+	managed.mutex.Lock()
+	defer managed.mutex.Unlock()
+
+	managed.retryAt = time.Time{}
+}
+
+func (managed *managedStream) RetryAt() (time.Time, bool) {
+	managed.mutex.Lock()
+	defer managed.mutex.Unlock()
+
+	return managed.retryAt, len(managed.pending) != 0
+}
+
+func (managed *managedStream) Discard() int {
+	managed.mutex.Lock()
+	defer managed.mutex.Unlock()
+
+	count := len(managed.pending)
+	managed.pending = nil
+
+	return count
+}
+
+func (managed *managedStream) retain(
+	backlog []Entry,
+	policy retryPolicy,
+) int {
+	dropped := 0
+	if policy.maxPending > 0 && len(backlog) > policy.maxPending {
+		dropped = len(backlog) - policy.maxPending
+		backlog = backlog[dropped:]
+	}
+
+	managed.pending = append([]Entry(nil), backlog...)
+
+	return dropped
+}
+
+func (policy retryPolicy) delay(
+	failures int,
+) time.Duration {
+	delay := policy.backoff
+	for i := 1; i < failures && delay < policy.maxBackoff; i++ {
+		delay *= 2
+	}
+
+	if policy.maxBackoff > 0 && delay > policy.maxBackoff {
+		delay = policy.maxBackoff
+	}
+
+	return delay
+}
+
+func deliverEntry(
+	stream Stream,
+	entry Entry,
+) error {
+	if entry.Channel != "" {
+		return stream.Signal(
+			entry.Timestamp,
+			entry.Level,
+			entry.Channel,
+			entry.Message,
+			entry.Ctx)
+	}
+
+	return stream.Broadcast(
+		entry.Timestamp,
+		entry.Level,
+		entry.Message,
+		entry.Ctx)
+}
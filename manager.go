@@ -1,112 +1,663 @@
 package log
 
 import (
+	"context"
+	"errors"
+	"slices"
 	"sync"
 	"time"
 
 	flam "github.com/happyhippyhippo/flam"
 )
 
-type regEntry struct {
-	timestamp time.Time
-	level     Level
-	channel   string
-	message   string
-	ctx       flam.Bag
-}
-
 type manager struct {
-	streams map[string]Stream
-	buffer  []regEntry
-	mutex   sync.Locker
+	streams    map[string]*managedStream
+	buffer     []Entry
+	mutex      sync.Locker
+	levels     *levelCache
+	cond       *sync.Cond
+	maxEntries int
+	maxBytes   int
+	overflow   string
+	blockFor   time.Duration
+	size       int
+	dropped    uint64
+	reported   uint64
+	retry      retryPolicy
+	retrier    *time.Timer
+	async      bool
+	capacity   int
+	drain      time.Duration
+	errs       []error
+	mode       string
+	flushOn    Level
+	extractors []ContextExtractor
+	caller     bool
+	stackAt    Level
 }
 
 func newManager() *manager {
+	mutex := &sync.Mutex{}
+
 	return &manager{
-		streams: map[string]Stream{},
-		buffer:  []regEntry{},
-		mutex:   &sync.Mutex{},
+		streams:  map[string]*managedStream{},
+		buffer:   []Entry{},
+		mutex:    mutex,
+		levels:   newLevelCache(),
+		cond:     sync.NewCond(mutex),
+		overflow: OverflowDropNewest,
+		blockFor: DefaultBufferBlockTimeout,
+		mode:     FlusherModePeriodic,
+		retry: retryPolicy{
+			backoff:    DefaultRetryBackoff,
+			maxBackoff: DefaultRetryMaxBackoff,
+			maxPending: DefaultRetryMaxPending,
+		},
 	}
 }
 
-func (manager *manager) Signal(
-	level Level,
-	channel,
-	message string,
-	ctx ...flam.Bag,
+func (manager *manager) SetRetryPolicy(
+	backoff,
+	maxBackoff time.Duration,
+	maxPending int,
+) {
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+
+	manager.retry = retryPolicy{
+		backoff:    backoff,
+		maxBackoff: maxBackoff,
+		maxPending: maxPending,
+	}
+}
+
+func (manager *manager) SetAsync(
+	enabled bool,
+	capacity int,
+	drain time.Duration,
+) {
+	manager.mutex.Lock()
+
+	manager.async = enabled
+	manager.capacity = capacity
+	manager.drain = drain
+
+	stopped := map[*managedStream]*streamWorker{}
+	for id, managed := range manager.streams {
+		switch {
+		case enabled && managed.worker == nil:
+			manager.startWorker(id, managed)
+		case !enabled && managed.worker != nil:
+			stopped[managed] = manager.stopWorker(managed)
+		}
+	}
+	manager.mutex.Unlock()
+
+	for managed, worker := range stopped {
+		manager.settleWorker(managed, worker, drain)
+	}
+}
+
+func (manager *manager) SetFlushMode(
+	mode string,
+	flushOn Level,
 ) error {
-	context := flam.Bag{}
-	for _, c := range ctx {
-		context.Merge(c)
+	switch mode {
+	case FlusherModePeriodic, FlusherModeSync:
+	default:
+		return newErrUnknownFlusherMode(mode)
+	}
+
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+
+	manager.mode = mode
+	manager.flushOn = flushOn
+
+	return nil
+}
+
+func (manager *manager) SetExtractors(
+	extractors []ContextExtractor,
+) {
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+
+	manager.extractors = extractors
+}
+
+func (manager *manager) SetCallerCapture(
+	caller bool,
+	stacktraceAt Level,
+) {
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+
+	manager.caller = caller
+	manager.stackAt = stacktraceAt
+}
+
+func (manager *manager) SetBufferLimits(
+	maxEntries,
+	maxBytes int,
+	overflow string,
+	blockTimeout time.Duration,
+) error {
+	switch overflow {
+	case OverflowBlock, OverflowDropNewest, OverflowDropOldest, OverflowFlushInline:
+	default:
+		return newErrUnknownOverflowPolicy(overflow)
 	}
 
 	manager.mutex.Lock()
 	defer manager.mutex.Unlock()
 
-	manager.buffer = append(manager.buffer, regEntry{
-		timestamp: time.Now(),
-		level:     level,
-		channel:   channel,
-		message:   message,
-		ctx:       context})
+	manager.maxEntries = maxEntries
+	manager.maxBytes = maxBytes
+	manager.overflow = overflow
+	manager.blockFor = blockTimeout
+	manager.cond.Broadcast()
 
 	return nil
 }
 
+func (manager *manager) DroppedEntries() uint64 {
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+
+	manager.collect()
+
+	return manager.dropped
+}
+
+func (manager *manager) IsEnabled(
+	level Level,
+	channel string,
+) bool {
+	accepted := manager.levels.Get(channel, func(channel string) Level {
+		manager.mutex.Lock()
+		defer manager.mutex.Unlock()
+
+		return maxAcceptedLevel(manager.streams, channel)
+	})
+
+	return accepted != None && level <= accepted
+}
+
+func (manager *manager) Signal(
+	level Level,
+	channel,
+	message string,
+	ctx ...flam.Bag,
+) error {
+	return manager.signal(time.Time{}, 0, level, channel, message, ctx)
+}
+
 func (manager *manager) Broadcast(
 	level Level,
 	message string,
 	ctx ...flam.Bag,
 ) error {
+	return manager.signal(time.Time{}, 0, level, "", message, ctx)
+}
+
+func (manager *manager) SignalCtx(
+	ctx context.Context,
+	level Level,
+	channel,
+	message string,
+	bag ...flam.Bag,
+) error {
+	if !manager.IsEnabled(level, channel) {
+		return nil
+	}
+
+	return manager.signal(time.Time{}, 0, level, channel, message, extractContext(ctx, manager.activeExtractors(), bag))
+}
+
+func (manager *manager) BroadcastCtx(
+	ctx context.Context,
+	level Level,
+	message string,
+	bag ...flam.Bag,
+) error {
+	if !manager.IsEnabled(level, "") {
+		return nil
+	}
+
+	return manager.signal(time.Time{}, 0, level, "", message, extractContext(ctx, manager.activeExtractors(), bag))
+}
+
+func (manager *manager) signal(
+	timestamp time.Time,
+	pc uintptr,
+	level Level,
+	channel,
+	message string,
+	ctx []flam.Bag,
+) error {
+	if !manager.IsEnabled(level, channel) {
+		return nil
+	}
+
 	context := flam.Bag{}
 	for _, c := range ctx {
 		context.Merge(c)
 	}
+	manager.annotate(context, level, pc)
+
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+
+	return manager.enqueue(Entry{
+		Timestamp: timestamp,
+		Level:     level,
+		Channel:   channel,
+		Message:   message,
+		Ctx:       context,
+	})
+}
+
+func (manager *manager) annotate(
+	ctx flam.Bag,
+	level Level,
+	pc uintptr,
+) {
+	manager.mutex.Lock()
+	caller := manager.caller
+	stackAt := manager.stackAt
+	manager.mutex.Unlock()
+
+	if caller {
+		captureCaller(ctx, pc)
+	}
+	if stackAt != None && level <= stackAt {
+		captureStacktrace(ctx)
+	}
+}
 
+func (manager *manager) activeExtractors() []ContextExtractor {
 	manager.mutex.Lock()
 	defer manager.mutex.Unlock()
 
-	manager.buffer = append(manager.buffer, regEntry{
-		timestamp: time.Now(),
-		level:     level,
-		channel:   "",
-		message:   message,
-		ctx:       context,
-	})
+	return manager.extractors
+}
 
-	return nil
+func (manager *manager) enqueue(
+	entry Entry,
+) error {
+	flush, e := manager.push(entry)
+	if e != nil || !flush {
+		return e
+	}
+
+	return manager.Flush()
+}
+
+func (manager *manager) push(
+	entry Entry,
+) (bool, error) {
+	size := entrySize(entry)
+
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+
+	var deadline time.Time
+	for manager.isFull(size) {
+		switch manager.overflow {
+		case OverflowBlock:
+			if deadline.IsZero() {
+				deadline = time.Now().Add(manager.blockFor)
+				timer := time.AfterFunc(manager.blockFor, func() {
+					manager.mutex.Lock()
+					defer manager.mutex.Unlock()
+
+					manager.cond.Broadcast()
+				})
+				defer timer.Stop()
+			}
+			if !time.Now().Before(deadline) {
+				manager.dropped++
+				return false, nil
+			}
+			manager.cond.Wait()
+		case OverflowDropOldest:
+			manager.size -= entrySize(manager.buffer[0])
+			manager.buffer = manager.buffer[1:]
+			manager.dropped++
+		case OverflowFlushInline:
+			manager.mutex.Unlock()
+			e := manager.Flush()
+			manager.mutex.Lock()
+			if e != nil && manager.isFull(size) {
+				manager.dropped++
+				return false, e
+			}
+		default:
+			manager.dropped++
+			return false, nil
+		}
+	}
+
+	manager.buffer = append(manager.buffer, entry)
+	manager.size += size
+
+	return manager.mode == FlusherModeSync ||
+		(manager.flushOn != None && entry.Level <= manager.flushOn), nil
+}
+
+func (manager *manager) isFull(
+	size int,
+) bool {
+	if len(manager.buffer) == 0 {
+		return false
+	}
+
+	return (manager.maxEntries > 0 && len(manager.buffer) >= manager.maxEntries) ||
+		(manager.maxBytes > 0 && manager.size+size > manager.maxBytes)
 }
 
 func (manager *manager) Flush() error {
 	manager.mutex.Lock()
 	defer manager.mutex.Unlock()
 
-	for _, entry := range manager.buffer {
-		for _, stream := range manager.streams {
-			if entry.channel != "" {
-				e := stream.Signal(
-					entry.timestamp,
-					entry.level,
-					entry.channel,
-					entry.message,
-					entry.ctx)
-				if e != nil {
-					return e
-				}
-			} else {
-				e := stream.Broadcast(
-					entry.timestamp,
-					entry.level,
-					entry.message,
-					entry.ctx)
-				if e != nil {
-					return e
-				}
+	manager.collect()
+
+	entries := manager.pull()
+	now := time.Now()
+	errs := manager.errs
+	manager.errs = nil
+	for _, id := range manager.sortedIds() {
+		managed := manager.streams[id]
+		if managed.worker != nil {
+			manager.dropped += uint64(managed.worker.Enqueue(entries))
+			continue
+		}
+
+		dropped, e := managed.Deliver(entries, now, manager.retry)
+		manager.dropped += uint64(dropped)
+		if e != nil {
+			errs = append(errs, newErrStreamDelivery(id, e))
+		}
+	}
+	manager.scheduleRetry()
+
+	return errors.Join(errs...)
+}
+
+func (manager *manager) scheduleRetry() {
+	var next time.Time
+	for _, managed := range manager.streams {
+		if managed.worker != nil {
+			continue
+		}
+		if at, pending := managed.RetryAt(); pending && (next.IsZero() || at.Before(next)) {
+			next = at
+		}
+	}
+
+	if manager.retrier != nil {
+		manager.retrier.Stop()
+		manager.retrier = nil
+	}
+	if !next.IsZero() {
+		manager.retrier = time.AfterFunc(max(time.Until(next), minRetryInterval), manager.retryPending)
+	}
+}
+
+func (manager *manager) retryPending() {
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+
+	now := time.Now()
+	for _, id := range manager.sortedIds() {
+		managed := manager.streams[id]
+		if managed.worker != nil {
+			continue
+		}
+		if at, pending := managed.RetryAt(); !pending || now.Before(at) {
+			continue
+		}
+
+		dropped, _ := managed.Deliver(nil, now, manager.retry)
+		manager.dropped += uint64(dropped)
+	}
+	manager.scheduleRetry()
+}
+
+func (manager *manager) Shutdown(
+	timeout time.Duration,
+	owned func(id string) bool,
+) error {
+	deadline := time.Now().Add(timeout)
+
+	manager.mutex.Lock()
+
+	if manager.retrier != nil {
+		manager.retrier.Stop()
+		manager.retrier = nil
+	}
+
+	manager.collect()
+	dropped := manager.dropped
+
+	entries := manager.pull()
+	errs := manager.errs
+	manager.errs = nil
+	ids := manager.sortedIds()
+	for _, id := range ids {
+		managed := manager.streams[id]
+		switch {
+		case managed.worker != nil:
+			manager.dropped += uint64(managed.worker.Enqueue(entries))
+		case time.Now().After(deadline):
+			manager.dropped += uint64(len(entries))
+		default:
+			managed.Expedite()
+			count, e := managed.Deliver(entries, time.Now(), manager.retry)
+			manager.dropped += uint64(count)
+			if e != nil {
+				errs = append(errs, newErrStreamDelivery(id, e))
+			}
+		}
+	}
+
+	streams := manager.streams
+	workers := map[string]*streamWorker{}
+	for _, id := range ids {
+		managed := streams[id]
+		if worker, _ := manager.detach(managed); worker != nil {
+			workers[id] = worker
+		}
+		if filterable, ok := managed.stream.(filterableStream); ok {
+			filterable.observe(nil)
+		}
+	}
+
+	manager.streams = map[string]*managedStream{}
+	manager.levels.Invalidate()
+	manager.mutex.Unlock()
+
+	for _, id := range ids {
+		managed := streams[id]
+		if worker := workers[id]; worker != nil && !manager.settleWorker(managed, worker, max(time.Until(deadline), 0)) {
+			if !owned(id) {
+				manager.handoff(managed, worker)
+			}
+			continue
+		}
+		if !owned(id) {
+			if e := managed.stream.Close(); e != nil {
+				errs = append(errs, e)
 			}
 		}
 	}
 
-	manager.buffer = []regEntry{}
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+
+	errs = append(errs, manager.errs...)
+	manager.errs = nil
+
+	if abandoned := manager.dropped - dropped; abandoned > 0 {
+		errs = append(errs, newErrAbandonedEntries(abandoned))
+	}
+	manager.reported = manager.dropped
+
+	return errors.Join(errs...)
+}
+
+func (manager *manager) pull() []Entry {
+	entries := manager.buffer
+	if manager.dropped != manager.reported {
+		entries = append(entries, Entry{
+			Timestamp: time.Now(),
+			Level:     Warning,
+			Message:   "log buffer overflow",
+			Ctx:       flam.Bag{"dropped": manager.dropped - manager.reported},
+		})
+		manager.reported = manager.dropped
+	}
+
+	manager.buffer = []Entry{}
+	manager.size = 0
+	manager.cond.Broadcast()
+
+	return entries
+}
+
+func (manager *manager) collect() {
+	for _, id := range manager.sortedIds() {
+		if worker := manager.streams[id].worker; worker != nil {
+			dropped, errs := worker.Collect()
+			manager.dropped += dropped
+			manager.errs = append(manager.errs, errs...)
+		}
+	}
+}
+
+func (manager *manager) record(
+	id string,
+	dropped int,
+	e error,
+) {
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+
+	manager.dropped += uint64(dropped)
+	if e != nil {
+		manager.errs = append(manager.errs, newErrStreamDelivery(id, e))
+	}
+}
+
+func (manager *manager) startWorker(
+	id string,
+	managed *managedStream,
+) {
+	managed.worker = newStreamWorker(id, managed, manager.retry, manager.capacity)
+}
+
+func (manager *manager) stopWorker(
+	managed *managedStream,
+) *streamWorker {
+	worker := managed.worker
+	managed.worker = nil
+	worker.Stop()
+
+	return worker
+}
+
+func (manager *manager) settleWorker(
+	managed *managedStream,
+	worker *streamWorker,
+	timeout time.Duration,
+) bool {
+	drained := worker.Drain(timeout)
+
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+
+	if drained {
+		manager.dropped += uint64(managed.Discard())
+	} else {
+		manager.dropped += uint64(len(worker.queue))
+	}
+
+	dropped, errs := worker.Collect()
+	manager.dropped += dropped
+	manager.errs = append(manager.errs, errs...)
+
+	return drained
+}
+
+func (manager *manager) handoff(
+	managed *managedStream,
+	worker *streamWorker,
+) {
+	worker.Handoff(func() {
+		if e := managed.stream.Close(); e != nil {
+			manager.mutex.Lock()
+			defer manager.mutex.Unlock()
+
+			manager.errs = append(manager.errs, e)
+		}
+	})
+}
+
+func (manager *manager) sortedIds() []string {
+	ids := make([]string, 0, len(manager.streams))
+	for id := range manager.streams {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+
+	return ids
+}
+
+func (manager *manager) detach(
+	managed *managedStream,
+) (*streamWorker, time.Duration) {
+	if managed.worker != nil {
+		return manager.stopWorker(managed), manager.drain
+	}
+
+	manager.dropped += uint64(managed.Discard())
+
+	return nil, 0
+}
+
+func (manager *manager) release(
+	id string,
+	managed *managedStream,
+	worker *streamWorker,
+	timeout time.Duration,
+) error {
+	filterable, isFilterable := managed.stream.(filterableStream)
+	if worker != nil && !manager.settleWorker(managed, worker, timeout) {
+		if isFilterable {
+			filterable.observe(nil)
+		}
+		manager.handoff(managed, worker)
+		return nil
+	}
+
+	if e := managed.stream.Close(); e != nil {
+		manager.mutex.Lock()
+		defer manager.mutex.Unlock()
+
+		if _, taken := manager.streams[id]; !taken {
+			if manager.async {
+				manager.startWorker(id, managed)
+			}
+			manager.streams[id] = managed
+			manager.levels.Invalidate()
+		}
+		return e
+	}
+	if isFilterable {
+		filterable.observe(nil)
+	}
 
 	return nil
 }
@@ -140,12 +691,12 @@ func (manager *manager) GetStream(
 	manager.mutex.Lock()
 	defer manager.mutex.Unlock()
 
-	stream, ok := manager.streams[id]
+	managed, ok := manager.streams[id]
 	if !ok {
 		return nil, newErrStreamNotFound(id)
 	}
 
-	return stream, nil
+	return managed.stream, nil
 }
 
 func (manager *manager) AddStream(
@@ -162,7 +713,20 @@ func (manager *manager) AddStream(
 	manager.mutex.Lock()
 	defer manager.mutex.Unlock()
 
-	manager.streams[id] = stream
+	managed := newManagedStream(stream)
+	if manager.async {
+		manager.startWorker(id, managed)
+	}
+	manager.streams[id] = managed
+	if filterable, ok := stream.(filterableStream); ok {
+		filterable.observe(manager.levels.Invalidate)
+	}
+	if reporting, ok := stream.(reportingWriter); ok {
+		reporting.report(func(dropped int, e error) {
+			manager.record(id, dropped, e)
+		})
+	}
+	manager.levels.Invalidate()
 
 	return nil
 }
@@ -171,31 +735,42 @@ func (manager *manager) RemoveStream(
 	id string,
 ) error {
 	manager.mutex.Lock()
-	defer manager.mutex.Unlock()
 
-	if _, ok := manager.streams[id]; !ok {
+	managed, ok := manager.streams[id]
+	if !ok {
+		manager.mutex.Unlock()
 		return newErrStreamNotFound(id)
 	}
 
-	if e := manager.streams[id].Close(); e != nil {
-		return e
-	}
 	delete(manager.streams, id)
+	manager.levels.Invalidate()
+	worker, timeout := manager.detach(managed)
+	manager.mutex.Unlock()
 
-	return nil
+	return manager.release(id, managed, worker, timeout)
 }
 
 func (manager *manager) RemoveAllStreams() error {
 	manager.mutex.Lock()
-	defer manager.mutex.Unlock()
 
-	for _, stream := range manager.streams {
-		if e := stream.Close(); e != nil {
-			return e
-		}
+	ids := manager.sortedIds()
+	streams := manager.streams
+	workers := map[string]*streamWorker{}
+	timeouts := map[string]time.Duration{}
+	for _, id := range ids {
+		workers[id], timeouts[id] = manager.detach(streams[id])
 	}
 
-	manager.streams = map[string]Stream{}
+	manager.streams = map[string]*managedStream{}
+	manager.levels.Invalidate()
+	manager.mutex.Unlock()
 
-	return nil
+	var errs []error
+	for _, id := range ids {
+		if e := manager.release(id, streams[id], workers[id], timeouts[id]); e != nil {
+			errs = append(errs, e)
+		}
+	}
+
+	return errors.Join(errs...)
 }
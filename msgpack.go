@@ -0,0 +1,404 @@
+package log
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"time"
+
+	flam "github.com/happyhippyhippo/flam"
+)
+
+const (
+	msgpackMaxLength = 1 << 20
+	msgpackMaxItems  = 1 << 16
+	msgpackMaxDepth  = 32
+)
+
+type msgpackBuffer []byte
+
+func (buffer msgpackBuffer) Nil() msgpackBuffer {
+	return append(buffer, 0xc0)
+}
+
+func (buffer msgpackBuffer) Bool(
+	value bool,
+) msgpackBuffer {
+	if value {
+		return append(buffer, 0xc3)
+	}
+
+	return append(buffer, 0xc2)
+}
+
+func (buffer msgpackBuffer) Int(
+	value int64,
+) msgpackBuffer {
+	switch {
+	case value >= 0:
+		return buffer.Uint(uint64(value))
+	case value >= -32:
+		return append(buffer, byte(value))
+	case value >= math.MinInt8:
+		return append(buffer, 0xd0, byte(value))
+	case value >= math.MinInt16:
+		return binary.BigEndian.AppendUint16(append(buffer, 0xd1), uint16(value))
+	case value >= math.MinInt32:
+		return binary.BigEndian.AppendUint32(append(buffer, 0xd2), uint32(value))
+	default:
+		return binary.BigEndian.AppendUint64(append(buffer, 0xd3), uint64(value))
+	}
+}
+
+func (buffer msgpackBuffer) Uint(
+	value uint64,
+) msgpackBuffer {
+	switch {
+	case value <= math.MaxInt8:
+		return append(buffer, byte(value))
+	case value <= math.MaxUint8:
+		return append(buffer, 0xcc, byte(value))
+	case value <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(buffer, 0xcd), uint16(value))
+	case value <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(buffer, 0xce), uint32(value))
+	default:
+		return binary.BigEndian.AppendUint64(append(buffer, 0xcf), value)
+	}
+}
+
+func (buffer msgpackBuffer) Float(
+	value float64,
+) msgpackBuffer {
+	return binary.BigEndian.AppendUint64(append(buffer, 0xcb), math.Float64bits(value))
+}
+
+func (buffer msgpackBuffer) String(
+	value string,
+) msgpackBuffer {
+	size := len(value)
+	switch {
+	case size < 32:
+		buffer = append(buffer, 0xa0|byte(size))
+	case size <= math.MaxUint8:
+		buffer = append(buffer, 0xd9, byte(size))
+	case size <= math.MaxUint16:
+		buffer = binary.BigEndian.AppendUint16(append(buffer, 0xda), uint16(size))
+	default:
+		buffer = binary.BigEndian.AppendUint32(append(buffer, 0xdb), uint32(size))
+	}
+
+	return append(buffer, value...)
+}
+
+func (buffer msgpackBuffer) Bin(
+	value []byte,
+) msgpackBuffer {
+	size := len(value)
+	switch {
+	case size <= math.MaxUint8:
+		buffer = append(buffer, 0xc4, byte(size))
+	case size <= math.MaxUint16:
+		buffer = binary.BigEndian.AppendUint16(append(buffer, 0xc5), uint16(size))
+	default:
+		buffer = binary.BigEndian.AppendUint32(append(buffer, 0xc6), uint32(size))
+	}
+
+	return append(buffer, value...)
+}
+
+func (buffer msgpackBuffer) Array(
+	size int,
+) msgpackBuffer {
+	switch {
+	case size < 16:
+		return append(buffer, 0x90|byte(size))
+	case size <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(buffer, 0xdc), uint16(size))
+	default:
+		return binary.BigEndian.AppendUint32(append(buffer, 0xdd), uint32(size))
+	}
+}
+
+func (buffer msgpackBuffer) Map(
+	size int,
+) msgpackBuffer {
+	switch {
+	case size < 16:
+		return append(buffer, 0x80|byte(size))
+	case size <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(buffer, 0xde), uint16(size))
+	default:
+		return binary.BigEndian.AppendUint32(append(buffer, 0xdf), uint32(size))
+	}
+}
+
+func (buffer msgpackBuffer) EventTime(
+	value time.Time,
+) msgpackBuffer {
+	buffer = binary.BigEndian.AppendUint32(append(buffer, 0xd7, 0x00), uint32(value.Unix()))
+
+	return binary.BigEndian.AppendUint32(buffer, uint32(value.Nanosecond()))
+}
+
+func (buffer msgpackBuffer) Value(
+	value any,
+) msgpackBuffer {
+	switch typedValue := value.(type) {
+	case nil:
+		return buffer.Nil()
+	case bool:
+		return buffer.Bool(typedValue)
+	case int:
+		return buffer.Int(int64(typedValue))
+	case int8:
+		return buffer.Int(int64(typedValue))
+	case int16:
+		return buffer.Int(int64(typedValue))
+	case int32:
+		return buffer.Int(int64(typedValue))
+	case int64:
+		return buffer.Int(typedValue)
+	case uint:
+		return buffer.Uint(uint64(typedValue))
+	case uint8:
+		return buffer.Uint(uint64(typedValue))
+	case uint16:
+		return buffer.Uint(uint64(typedValue))
+	case uint32:
+		return buffer.Uint(uint64(typedValue))
+	case uint64:
+		return buffer.Uint(typedValue)
+	case float32:
+		return buffer.Float(float64(typedValue))
+	case float64:
+		return buffer.Float(typedValue)
+	case string:
+		return buffer.String(typedValue)
+	case []byte:
+		return buffer.Bin(typedValue)
+	case time.Time:
+		return buffer.String(typedValue.Format(time.RFC3339Nano))
+	case time.Duration:
+		return buffer.String(typedValue.String())
+	case error:
+		return buffer.String(typedValue.Error())
+	case flam.Bag:
+		return buffer.bag(typedValue)
+	case map[string]any:
+		return buffer.bag(typedValue)
+	case []any:
+		buffer = buffer.Array(len(typedValue))
+		for _, item := range typedValue {
+			buffer = buffer.Value(item)
+		}
+		return buffer
+	default:
+		return buffer.String(fmt.Sprint(typedValue))
+	}
+}
+
+func (buffer msgpackBuffer) bag(
+	value map[string]any,
+) msgpackBuffer {
+	keys := make([]string, 0, len(value))
+	for key := range value {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	buffer = buffer.Map(len(keys))
+	for _, key := range keys {
+		buffer = buffer.String(key).Value(value[key])
+	}
+
+	return buffer
+}
+
+func msgpackDecode(
+	reader io.Reader,
+) (any, error) {
+	return msgpackDecodeValue(reader, 0)
+}
+
+func msgpackDecodeValue(
+	reader io.Reader,
+	depth int,
+) (any, error) {
+	read := func(size int) ([]byte, error) {
+		data := make([]byte, size)
+		_, e := io.ReadFull(reader, data)
+		return data, e
+	}
+
+	header, e := read(1)
+	if e != nil {
+		return nil, e
+	}
+
+	code := header[0]
+	switch {
+	case code <= 0x7f:
+		return int64(code), nil
+	case code >= 0xe0:
+		return int64(int8(code)), nil
+	case code&0xf0 == 0x80:
+		return msgpackDecodeMap(reader, int(code&0x0f), depth)
+	case code&0xf0 == 0x90:
+		return msgpackDecodeArray(reader, int(code&0x0f), depth)
+	case code&0xe0 == 0xa0:
+		data, e := read(int(code & 0x1f))
+		return string(data), e
+	}
+
+	size := func(width int, limit int) (int, error) {
+		data, e := read(width)
+		if e != nil {
+			return 0, e
+		}
+
+		var n int
+		switch width {
+		case 1:
+			n = int(data[0])
+		case 2:
+			n = int(binary.BigEndian.Uint16(data))
+		default:
+			n = int(binary.BigEndian.Uint32(data))
+		}
+		if n > limit {
+			return 0, newErrMsgpackTooLarge(n)
+		}
+
+		return n, nil
+	}
+
+	switch code {
+	case 0xc0:
+		return nil, nil
+	case 0xc2:
+		return false, nil
+	case 0xc3:
+		return true, nil
+	case 0xc4, 0xc5, 0xc6:
+		n, e := size(1<<(code-0xc4), msgpackMaxLength)
+		if e != nil {
+			return nil, e
+		}
+		return read(n)
+	case 0xd9, 0xda, 0xdb:
+		n, e := size(1<<(code-0xd9), msgpackMaxLength)
+		if e != nil {
+			return nil, e
+		}
+		data, e := read(n)
+		return string(data), e
+	case 0xdc, 0xdd:
+		n, e := size(2<<(code-0xdc), msgpackMaxItems)
+		if e != nil {
+			return nil, e
+		}
+		return msgpackDecodeArray(reader, n, depth)
+	case 0xde, 0xdf:
+		n, e := size(2<<(code-0xde), msgpackMaxItems)
+		if e != nil {
+			return nil, e
+		}
+		return msgpackDecodeMap(reader, n, depth)
+	case 0xca:
+		data, e := read(4)
+		if e != nil {
+			return nil, e
+		}
+		return float64(math.Float32frombits(binary.BigEndian.Uint32(data))), nil
+	case 0xcb:
+		data, e := read(8)
+		if e != nil {
+			return nil, e
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(data)), nil
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		data, e := read(1 << (code - 0xcc))
+		if e != nil {
+			return nil, e
+		}
+		var value uint64
+		for _, b := range data {
+			value = value<<8 | uint64(b)
+		}
+		return value, nil
+	case 0xd0, 0xd1, 0xd2, 0xd3:
+		width := 1 << (code - 0xd0)
+		data, e := read(width)
+		if e != nil {
+			return nil, e
+		}
+		var value uint64
+		for _, b := range data {
+			value = value<<8 | uint64(b)
+		}
+		shift := 64 - 8*width
+		return int64(value<<shift) >> shift, nil
+	case 0xd7:
+		data, e := read(9)
+		if e != nil {
+			return nil, e
+		}
+		if data[0] != 0x00 {
+			return nil, newErrInvalidMsgpack(code)
+		}
+		return time.Unix(
+			int64(binary.BigEndian.Uint32(data[1:5])),
+			int64(binary.BigEndian.Uint32(data[5:9]))).UTC(), nil
+	default:
+		return nil, newErrInvalidMsgpack(code)
+	}
+}
+
+func msgpackDecodeArray(
+	reader io.Reader,
+	size int,
+	depth int,
+) ([]any, error) {
+	if depth >= msgpackMaxDepth {
+		return nil, newErrMsgpackTooLarge(depth)
+	}
+
+	values := make([]any, 0, size)
+	for range size {
+		value, e := msgpackDecodeValue(reader, depth+1)
+		if e != nil {
+			return nil, e
+		}
+		values = append(values, value)
+	}
+
+	return values, nil
+}
+
+func msgpackDecodeMap(
+	reader io.Reader,
+	size int,
+	depth int,
+) (map[string]any, error) {
+	if depth >= msgpackMaxDepth {
+		return nil, newErrMsgpackTooLarge(depth)
+	}
+
+	values := make(map[string]any, size)
+	for range size {
+		key, e := msgpackDecodeValue(reader, depth+1)
+		if e != nil {
+			return nil, e
+		}
+		value, e := msgpackDecodeValue(reader, depth+1)
+		if e != nil {
+			return nil, e
+		}
+		values[fmt.Sprint(key)] = value
+	}
+
+	return values, nil
+}
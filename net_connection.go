@@ -0,0 +1,227 @@
+package log
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"time"
+
+	"github.com/spf13/afero"
+
+	flam "github.com/happyhippyhippo/flam"
+	filesystem "github.com/happyhippyhippo/flam-filesystem"
+)
+
+type netConnectionOptions struct {
+	network   string
+	address   string
+	timeout   time.Duration
+	tls       *tls.Config
+	retry     retryPolicy
+	handshake func(conn net.Conn) error
+}
+
+type netConnection struct {
+	options  netConnectionOptions
+	conn     net.Conn
+	failures int
+	retryAt  time.Time
+}
+
+func newNetConnection(
+	options netConnectionOptions,
+) *netConnection {
+	return &netConnection{
+		options: options,
+	}
+}
+
+func (connection *netConnection) Write(
+	payload []byte,
+) (int, error) {
+	if connection.conn == nil {
+		if e := connection.dial(); e != nil {
+			return 0, e
+		}
+	}
+
+	n, e := connection.write(payload)
+	if e == nil || n != 0 {
+		return n, e
+	}
+
+	if e := connection.dial(); e != nil {
+		return 0, e
+	}
+
+	return connection.write(payload)
+}
+
+func (connection *netConnection) write(
+	payload []byte,
+) (int, error) {
+	if connection.options.timeout > 0 {
+		_ = connection.conn.SetWriteDeadline(time.Now().Add(connection.options.timeout))
+	}
+
+	n, e := connection.conn.Write(payload)
+	if e == nil {
+		return n, nil
+	}
+
+	_ = connection.Close()
+	if n != 0 {
+		return n, newErrPartialWrite(connection.options.address, e)
+	}
+
+	return 0, e
+}
+
+func (connection *netConnection) Read(
+	payload []byte,
+) (int, error) {
+	if connection.conn == nil {
+		return 0, net.ErrClosed
+	}
+
+	return connection.conn.Read(payload)
+}
+
+func (connection *netConnection) SetReadDeadline(
+	deadline time.Time,
+) error {
+	if connection.conn == nil {
+		return net.ErrClosed
+	}
+
+	return connection.conn.SetReadDeadline(deadline)
+}
+
+func (connection *netConnection) Close() error {
+	if connection.conn == nil {
+		return nil
+	}
+
+	e := connection.conn.Close()
+	connection.conn = nil
+
+	return e
+}
+
+func (connection *netConnection) IsStream() bool {
+	switch connection.options.network {
+	case "udp", "udp4", "udp6", "unixgram":
+		return false
+	default:
+		return true
+	}
+}
+
+func (connection *netConnection) dial() error {
+	now := time.Now()
+	if connection.failures != 0 && now.Before(connection.retryAt) {
+		return newErrConnectionBackoff(connection.options.address)
+	}
+
+	e := connection.connect()
+	if e == nil && connection.options.handshake != nil {
+		if e = connection.options.handshake(connection.conn); e != nil {
+			_ = connection.Close()
+		}
+	}
+	if e != nil {
+		connection.failures++
+		connection.retryAt = now.Add(connection.options.retry.delay(connection.failures))
+		return e
+	}
+	connection.failures = 0
+
+	return nil
+}
+
+func (connection *netConnection) connect() error {
+	dialer := &net.Dialer{Timeout: connection.options.timeout}
+
+	if connection.options.tls != nil {
+		conn, e := tls.DialWithDialer(
+			dialer,
+			connection.options.network,
+			connection.options.address,
+			connection.options.tls)
+		if e != nil {
+			return e
+		}
+		connection.conn = conn
+
+		return nil
+	}
+
+	conn, e := dialer.Dial(
+		connection.options.network,
+		connection.options.address)
+	if e != nil {
+		return e
+	}
+	connection.conn = conn
+
+	return nil
+}
+
+func tlsConfigFrom(
+	config flam.Bag,
+	fileSystemFacade filesystem.Facade,
+) (*tls.Config, error) {
+	if !config.Bool("enabled") {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{
+		ServerName:         config.String("server_name"),
+		InsecureSkipVerify: config.Bool("insecure_skip_verify"),
+	}
+
+	caFile := config.String("ca_file")
+	certFile := config.String("cert_file")
+	keyFile := config.String("key_file")
+	if caFile == "" && certFile == "" && keyFile == "" {
+		return tlsConfig, nil
+	}
+
+	disk, e := fileSystemFacade.GetDisk(config.String("disk", DefaultDisk))
+	if e != nil {
+		return nil, e
+	}
+
+	if caFile != "" {
+		pem, e := afero.ReadFile(disk, caFile)
+		if e != nil {
+			return nil, newErrInvalidTlsConfig(caFile, e)
+		}
+
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, newErrInvalidTlsConfig(caFile, nil)
+		}
+	}
+
+	if certFile != "" || keyFile != "" {
+		certPem, e := afero.ReadFile(disk, certFile)
+		if e != nil {
+			return nil, newErrInvalidTlsConfig(certFile, e)
+		}
+
+		keyPem, e := afero.ReadFile(disk, keyFile)
+		if e != nil {
+			return nil, newErrInvalidTlsConfig(keyFile, e)
+		}
+
+		certificate, e := tls.X509KeyPair(certPem, keyPem)
+		if e != nil {
+			return nil, newErrInvalidTlsConfig(certFile, e)
+		}
+
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+
+	return tlsConfig, nil
+}
@@ -0,0 +1,308 @@
+package log
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var otlpSeverities = map[Level]struct {
+	number int
+	text   string
+}{
+	Fatal:   {21, "FATAL"},
+	Error:   {17, "ERROR"},
+	Warning: {13, "WARN"},
+	Notice:  {10, "NOTICE"},
+	Info:    {9, "INFO"},
+	Debug:   {5, "DEBUG"},
+}
+
+type otlpRecord struct {
+	item       httpBatchItem
+	attributes []contextField
+	traceId    []byte
+	spanId     []byte
+}
+
+type otlpScope struct {
+	name    string
+	records []otlpRecord
+}
+
+type otlpCodec struct {
+	format   string
+	channel  string
+	scope    string
+	resource []contextField
+}
+
+func newOtlpCodec(
+	format string,
+	channel string,
+	scope string,
+	resource []contextField,
+) (httpBatchCodec, error) {
+	switch format {
+	case "":
+		format = OtlpFormatProtobuf
+	case OtlpFormatProtobuf, OtlpFormatJson:
+	default:
+		return nil, newErrUnknownOtlpFormat(format)
+	}
+
+	switch channel {
+	case "":
+		channel = OtlpChannelAttribute
+	case OtlpChannelAttribute, OtlpChannelScope:
+	default:
+		return nil, newErrUnknownOtlpChannelMode(channel)
+	}
+
+	return &otlpCodec{
+		format:   format,
+		channel:  channel,
+		scope:    scope,
+		resource: resource,
+	}, nil
+}
+
+func (codec otlpCodec) Encode(
+	items []httpBatchItem,
+) (string, []byte, error) {
+	scopes := codec.group(items)
+	if codec.format == OtlpFormatJson {
+		return codec.json(scopes)
+	}
+
+	return "application/x-protobuf", codec.protobuf(scopes), nil
+}
+
+func (otlpCodec) Decode(
+	items []httpBatchItem,
+	status int,
+	_ []byte,
+) ([]httpBatchItem, int, error) {
+	if status < http.StatusOK || status >= http.StatusMultipleChoices {
+		return nil, len(items), newErrHttpStatus(status)
+	}
+
+	return nil, 0, nil
+}
+
+func (codec otlpCodec) group(
+	items []httpBatchItem,
+) []*otlpScope {
+	index := map[string]*otlpScope{}
+	var scopes []*otlpScope
+	for _, item := range items {
+		name := codec.scope
+		if codec.channel == OtlpChannelScope && item.entry.Channel != "" {
+			name = item.entry.Channel
+		}
+
+		scope, ok := index[name]
+		if !ok {
+			scope = &otlpScope{name: name}
+			index[name] = scope
+			scopes = append(scopes, scope)
+		}
+		scope.records = append(scope.records, codec.record(item))
+	}
+
+	slices.SortFunc(scopes, func(a, b *otlpScope) int {
+		return strings.Compare(a.name, b.name)
+	})
+
+	return scopes
+}
+
+func (codec otlpCodec) record(
+	item httpBatchItem,
+) otlpRecord {
+	record := otlpRecord{item: item}
+	if codec.channel == OtlpChannelAttribute && item.entry.Channel != "" {
+		record.attributes = append(record.attributes, contextField{key: "channel", value: item.entry.Channel})
+	}
+
+	for _, field := range flattenContext("", item.entry.Ctx) {
+		switch field.key {
+		case "trace_id":
+			if id, e := hex.DecodeString(logfmtSerializer{}.format(field.value)); e == nil && len(id) == 16 {
+				record.traceId = id
+				continue
+			}
+		case "span_id":
+			if id, e := hex.DecodeString(logfmtSerializer{}.format(field.value)); e == nil && len(id) == 8 {
+				record.spanId = id
+				continue
+			}
+		}
+		record.attributes = append(record.attributes, field)
+	}
+
+	return record
+}
+
+func (otlpCodec) body(
+	record otlpRecord,
+) string {
+	return string(bytes.TrimRight(record.item.payload, "\n"))
+}
+
+func (codec otlpCodec) protobuf(
+	scopes []*otlpScope,
+) []byte {
+	resource := protoBuffer{}
+	for _, attribute := range codec.resource {
+		resource = resource.Message(1, codec.protoKeyValue(attribute))
+	}
+
+	resourceLogs := protoBuffer{}.Message(1, resource)
+	for _, scope := range scopes {
+		scopeLogs := protoBuffer{}.Message(1, protoBuffer{}.String(1, scope.name))
+		for _, record := range scope.records {
+			severity := otlpSeverities[record.item.entry.Level]
+
+			message := protoBuffer{}.
+				Fixed64(1, uint64(record.item.entry.Timestamp.UnixNano())).
+				Int(2, int64(severity.number)).
+				String(3, severity.text).
+				Message(5, codec.protoValue(codec.body(record)))
+			for _, attribute := range record.attributes {
+				message = message.Message(6, codec.protoKeyValue(attribute))
+			}
+			message = message.
+				Bytes(9, record.traceId).
+				Bytes(10, record.spanId)
+
+			scopeLogs = scopeLogs.Message(2, message)
+		}
+		resourceLogs = resourceLogs.Message(2, scopeLogs)
+	}
+
+	return protoBuffer{}.Message(1, resourceLogs)
+}
+
+func (codec otlpCodec) protoKeyValue(
+	field contextField,
+) protoBuffer {
+	return protoBuffer{}.
+		String(1, field.key).
+		Message(2, codec.protoValue(field.value))
+}
+
+func (otlpCodec) protoValue(
+	value any,
+) protoBuffer {
+	switch v := value.(type) {
+	case string:
+		return protoBuffer{}.Message(1, protoBuffer(v))
+	case bool:
+		flag := uint64(0)
+		if v {
+			flag = 1
+		}
+		return binary.AppendUvarint(protoBuffer{}.tag(2, protoVarint), flag)
+	case int:
+		return binary.AppendUvarint(protoBuffer{}.tag(3, protoVarint), uint64(v))
+	case int64:
+		return binary.AppendUvarint(protoBuffer{}.tag(3, protoVarint), uint64(v))
+	case float64:
+		return binary.LittleEndian.AppendUint64(protoBuffer{}.tag(4, protoFixed64), math.Float64bits(v))
+	default:
+		return protoBuffer{}.Message(1, protoBuffer(logfmtSerializer{}.format(v)))
+	}
+}
+
+func (codec otlpCodec) json(
+	scopes []*otlpScope,
+) (string, []byte, error) {
+	type jsonRecord struct {
+		TimeUnixNano   string           `json:"timeUnixNano"`
+		SeverityNumber int              `json:"severityNumber"`
+		SeverityText   string           `json:"severityText"`
+		Body           map[string]any   `json:"body"`
+		Attributes     []map[string]any `json:"attributes,omitempty"`
+		TraceId        string           `json:"traceId,omitempty"`
+		SpanId         string           `json:"spanId,omitempty"`
+	}
+	type jsonScope struct {
+		Scope      map[string]string `json:"scope"`
+		LogRecords []jsonRecord      `json:"logRecords"`
+	}
+
+	var scopeLogs []jsonScope
+	for _, scope := range scopes {
+		var records []jsonRecord
+		for _, record := range scope.records {
+			severity := otlpSeverities[record.item.entry.Level]
+			records = append(records, jsonRecord{
+				TimeUnixNano:   strconv.FormatInt(record.item.entry.Timestamp.UnixNano(), 10),
+				SeverityNumber: severity.number,
+				SeverityText:   severity.text,
+				Body:           codec.jsonValue(codec.body(record)),
+				Attributes:     codec.jsonAttributes(record.attributes),
+				TraceId:        hex.EncodeToString(record.traceId),
+				SpanId:         hex.EncodeToString(record.spanId),
+			})
+		}
+		scopeLogs = append(scopeLogs, jsonScope{
+			Scope:      map[string]string{"name": scope.name},
+			LogRecords: records,
+		})
+	}
+
+	body, e := json.Marshal(map[string]any{
+		"resourceLogs": []any{
+			map[string]any{
+				"resource":  map[string]any{"attributes": codec.jsonAttributes(codec.resource)},
+				"scopeLogs": scopeLogs,
+			},
+		},
+	})
+	if e != nil {
+		return "", nil, e
+	}
+
+	return "application/json", body, nil
+}
+
+func (codec otlpCodec) jsonAttributes(
+	fields []contextField,
+) []map[string]any {
+	attributes := make([]map[string]any, 0, len(fields))
+	for _, field := range fields {
+		attributes = append(attributes, map[string]any{
+			"key":   field.key,
+			"value": codec.jsonValue(field.value),
+		})
+	}
+
+	return attributes
+}
+
+func (otlpCodec) jsonValue(
+	value any,
+) map[string]any {
+	switch v := value.(type) {
+	case string:
+		return map[string]any{"stringValue": v}
+	case bool:
+		return map[string]any{"boolValue": v}
+	case int:
+		return map[string]any{"intValue": strconv.Itoa(v)}
+	case int64:
+		return map[string]any{"intValue": strconv.FormatInt(v, 10)}
+	case float64:
+		return map[string]any{"doubleValue": v}
+	default:
+		return map[string]any{"stringValue": logfmtSerializer{}.format(v)}
+	}
+}
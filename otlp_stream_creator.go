@@ -0,0 +1,73 @@
+package log
+
+import (
+	"os"
+	"slices"
+	"sort"
+
+	flam "github.com/happyhippyhippo/flam"
+)
+
+type otlpStreamCreator struct {
+	streamCreator
+}
+
+func newOtlpStreamCreator(
+	serializerFactory serializerFactory,
+) StreamCreator {
+	return &otlpStreamCreator{
+		streamCreator: streamCreator{
+			serializerFactory: serializerFactory,
+		},
+	}
+}
+
+func (otlpStreamCreator) Accept(
+	config flam.Bag,
+) bool {
+	return config.String("driver") == StreamDriverOtlp &&
+		config.Has("url")
+}
+
+func (creator otlpStreamCreator) Create(
+	config flam.Bag,
+) (Stream, error) {
+	var serializer Serializer
+	if serializerId := config.String("serializer", DefaultSerializer); serializerId != "" {
+		var e error
+		if serializer, e = creator.serializerFactory.Get(serializerId); e != nil {
+			return nil, e
+		}
+	}
+
+	resource := flattenContext("", config.Bag("resource"))
+	if !slices.ContainsFunc(resource, func(field contextField) bool { return field.key == "host.name" }) {
+		if hostname, e := os.Hostname(); e == nil {
+			resource = append(resource, contextField{key: "host.name", value: hostname})
+		}
+	}
+
+	codec, e := newOtlpCodec(
+		config.String("format"),
+		config.String("channel"),
+		config.String("scope", "flam-log"),
+		resource)
+	if e != nil {
+		return nil, e
+	}
+
+	writer, e := newHttpBatchWriter(codec, httpBatchWriterOptionsFrom(config))
+	if e != nil {
+		return nil, e
+	}
+
+	channels := creator.getChannels(config.Slice("channels"))
+	sort.Strings(channels)
+
+	return newStream(
+		LevelFrom(config.Get("level"), DefaultLevel),
+		channels,
+		serializer,
+		writer,
+		true), nil
+}
@@ -0,0 +1,139 @@
+package log
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	flam "github.com/happyhippyhippo/flam"
+)
+
+func decodeProto(
+	t *testing.T,
+	data []byte,
+) map[int][]any {
+	fields := map[int][]any{}
+	for len(data) != 0 {
+		key, n := binary.Uvarint(data)
+		require.Positive(t, n)
+		data = data[n:]
+
+		field := int(key >> 3)
+		switch key & 7 {
+		case protoVarint:
+			value, n := binary.Uvarint(data)
+			require.Positive(t, n)
+			data = data[n:]
+			fields[field] = append(fields[field], value)
+		case protoFixed64:
+			fields[field] = append(fields[field], binary.LittleEndian.Uint64(data))
+			data = data[8:]
+		case protoBytes:
+			length, n := binary.Uvarint(data)
+			require.Positive(t, n)
+			fields[field] = append(fields[field], data[n:n+int(length)])
+			data = data[n+int(length):]
+		default:
+			require.Fail(t, "unexpected wire type")
+		}
+	}
+
+	return fields
+}
+
+func Test_otlpStream(t *testing.T) {
+	timestamp := time.Date(2021, 1, 2, 3, 4, 5, 6000000, time.UTC)
+	ctx := flam.Bag{
+		"trace_id": "0102030405060708090a0b0c0d0e0f10",
+		"span_id":  "0102030405060708",
+		"user":     "john",
+		"count":    3,
+	}
+
+	t.Run("should return an error on unknown format", func(t *testing.T) {
+		_, e := getDriverTestStream(t, StreamDriverOtlp, flam.Bag{"url": "http://localhost", "format": "invalid"})
+
+		assert.ErrorIs(t, e, ErrUnknownOtlpFormat)
+	})
+
+	t.Run("should return an error on unknown channel mode", func(t *testing.T) {
+		_, e := getDriverTestStream(t, StreamDriverOtlp, flam.Bag{"url": "http://localhost", "channel": "invalid"})
+
+		assert.ErrorIs(t, e, ErrUnknownOtlpChannelMode)
+	})
+
+	t.Run("should export the log records in json", func(t *testing.T) {
+		recorder := &httpRecorder{}
+		server := httptest.NewServer(recorder)
+		defer server.Close()
+
+		stream, e := getDriverTestStream(t, StreamDriverOtlp, flam.Bag{"url": server.URL, "format": OtlpFormatJson})
+		require.NoError(t, e)
+		defer func() { _ = stream.Close() }()
+
+		require.NoError(t, stream.Signal(timestamp, Warning, "http", "message", ctx))
+
+		require.Len(t, recorder.Await(t, 1), 1)
+		assert.Equal(t, "application/json", recorder.requests[0].Header.Get("Content-Type"))
+
+		var request map[string]any
+		require.NoError(t, json.Unmarshal([]byte(recorder.Bodies()[0]), &request))
+		assert.Equal(t, map[string]any{"resourceLogs": []any{map[string]any{
+			"resource": map[string]any{"attributes": []any{
+				map[string]any{"key": "host.name", "value": map[string]any{"stringValue": "host"}},
+				map[string]any{"key": "service.name", "value": map[string]any{"stringValue": "app"}},
+			}},
+			"scopeLogs": []any{map[string]any{
+				"scope": map[string]any{"name": "flam-log"},
+				"logRecords": []any{map[string]any{
+					"timeUnixNano":   "1609556645006000000",
+					"severityNumber": float64(13),
+					"severityText":   "WARN",
+					"body":           map[string]any{"stringValue": "message"},
+					"attributes": []any{
+						map[string]any{"key": "channel", "value": map[string]any{"stringValue": "http"}},
+						map[string]any{"key": "count", "value": map[string]any{"intValue": "3"}},
+						map[string]any{"key": "user", "value": map[string]any{"stringValue": "john"}},
+					},
+					"traceId": "0102030405060708090a0b0c0d0e0f10",
+					"spanId":  "0102030405060708",
+				}},
+			}},
+		}}}, request)
+	})
+
+	t.Run("should export the log records in protobuf with the channel as scope", func(t *testing.T) {
+		recorder := &httpRecorder{}
+		server := httptest.NewServer(recorder)
+		defer server.Close()
+
+		stream, e := getDriverTestStream(t, StreamDriverOtlp, flam.Bag{"url": server.URL, "channel": OtlpChannelScope})
+		require.NoError(t, e)
+		defer func() { _ = stream.Close() }()
+
+		require.NoError(t, stream.Signal(timestamp, Error, "http", "message", ctx))
+
+		require.Len(t, recorder.Await(t, 1), 1)
+		assert.Equal(t, "application/x-protobuf", recorder.requests[0].Header.Get("Content-Type"))
+
+		request := decodeProto(t, []byte(recorder.Bodies()[0]))
+		resourceLogs := decodeProto(t, request[1][0].([]byte))
+		scopeLogs := decodeProto(t, resourceLogs[2][0].([]byte))
+		scope := decodeProto(t, scopeLogs[1][0].([]byte))
+		record := decodeProto(t, scopeLogs[2][0].([]byte))
+
+		assert.Equal(t, []byte("http"), scope[1][0])
+		assert.Equal(t, uint64(timestamp.UnixNano()), record[1][0])
+		assert.Equal(t, uint64(17), record[2][0])
+		assert.Equal(t, []byte("ERROR"), record[3][0])
+		assert.Equal(t, []byte("message"), decodeProto(t, record[5][0].([]byte))[1][0])
+		assert.Len(t, record[6], 2)
+		assert.Equal(t, []byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}, record[9][0])
+		assert.Equal(t, []byte{1, 2, 3, 4, 5, 6, 7, 8}, record[10][0])
+	})
+}
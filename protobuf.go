@@ -0,0 +1,76 @@
+package log
+
+import (
+	"encoding/binary"
+)
+
+const (
+	protoVarint  = 0
+	protoFixed64 = 1
+	protoBytes   = 2
+)
+
+type protoBuffer []byte
+
+func (buffer protoBuffer) tag(
+	field int,
+	wire int,
+) protoBuffer {
+	return binary.AppendUvarint(buffer, uint64(field)<<3|uint64(wire))
+}
+
+func (buffer protoBuffer) Uint(
+	field int,
+	value uint64,
+) protoBuffer {
+	if value == 0 {
+		return buffer
+	}
+
+	return binary.AppendUvarint(buffer.tag(field, protoVarint), value)
+}
+
+func (buffer protoBuffer) Int(
+	field int,
+	value int64,
+) protoBuffer {
+	return buffer.Uint(field, uint64(value))
+}
+
+func (buffer protoBuffer) Fixed64(
+	field int,
+	value uint64,
+) protoBuffer {
+	if value == 0 {
+		return buffer
+	}
+
+	return binary.LittleEndian.AppendUint64(buffer.tag(field, protoFixed64), value)
+}
+
+func (buffer protoBuffer) Bytes(
+	field int,
+	value []byte,
+) protoBuffer {
+	if len(value) == 0 {
+		return buffer
+	}
+
+	return buffer.Message(field, value)
+}
+
+func (buffer protoBuffer) String(
+	field int,
+	value string,
+) protoBuffer {
+	return buffer.Bytes(field, []byte(value))
+}
+
+func (buffer protoBuffer) Message(
+	field int,
+	value protoBuffer,
+) protoBuffer {
+	buffer = binary.AppendUvarint(buffer.tag(field, protoBytes), uint64(len(value)))
+
+	return append(buffer, value...)
+}
@@ -32,10 +32,21 @@ func (*provider) Register(
 	registerer := flam.NewRegisterer()
 	registerer.Queue(newStringSerializerCreator, dig.Group(SerializerCreatorGroup))
 	registerer.Queue(newJsonSerializerCreator, dig.Group(SerializerCreatorGroup))
+	registerer.Queue(newLogfmtSerializerCreator, dig.Group(SerializerCreatorGroup))
+	registerer.Queue(newGelfSerializerCreator, dig.Group(SerializerCreatorGroup))
 	registerer.Queue(newSerializerFactory)
 	registerer.Queue(newConsoleStreamCreator, dig.Group(StreamCreatorGroup))
 	registerer.Queue(newFileStreamCreator, dig.Group(StreamCreatorGroup))
 	registerer.Queue(newRotatingFileStreamCreator, dig.Group(StreamCreatorGroup))
+	registerer.Queue(newSyslogStreamCreator, dig.Group(StreamCreatorGroup))
+	registerer.Queue(newSocketStreamCreator, dig.Group(StreamCreatorGroup))
+	registerer.Queue(newHttpStreamCreator, dig.Group(StreamCreatorGroup))
+	registerer.Queue(newLokiStreamCreator, dig.Group(StreamCreatorGroup))
+	registerer.Queue(newElasticsearchStreamCreator, dig.Group(StreamCreatorGroup))
+	registerer.Queue(newOtlpStreamCreator, dig.Group(StreamCreatorGroup))
+	registerer.Queue(newGelfStreamCreator, dig.Group(StreamCreatorGroup))
+	registerer.Queue(newFluentStreamCreator, dig.Group(StreamCreatorGroup))
+	registerer.Queue(newSlogStreamCreator, dig.Group(StreamCreatorGroup))
 	registerer.Queue(newStreamFactory)
 	registerer.Queue(newManager)
 	registerer.Queue(newFacade)
@@ -52,6 +63,12 @@ func (provider *provider) Boot(
 
 	executor := flam.NewExecutor()
 	executor.Queue(provider.bootDefaults)
+	executor.Queue(provider.bootBuffer)
+	executor.Queue(provider.bootRetry)
+	executor.Queue(provider.bootAsync)
+	executor.Queue(provider.bootFlushMode)
+	executor.Queue(provider.bootExtractors)
+	executor.Queue(provider.bootCaller)
 	executor.Queue(provider.bootStreams)
 	executor.Queue(provider.bootFlusher)
 
@@ -67,6 +84,7 @@ func (provider *provider) Close(
 
 	executor := flam.NewExecutor()
 	executor.Queue(provider.closeFlusher)
+	executor.Queue(provider.closeManager)
 	executor.Queue(provider.closeStreamFactory)
 	executor.Queue(provider.closeSerializerFactory)
 
@@ -83,6 +101,91 @@ func (*provider) bootDefaults(
 	return nil
 }
 
+func (*provider) bootBuffer(
+	configFacade config.Facade,
+	manager *manager,
+) error {
+	return manager.SetBufferLimits(
+		configFacade.Int(PathBufferMaxEntries),
+		configFacade.Int(PathBufferMaxBytes),
+		configFacade.String(PathBufferOverflow, OverflowDropNewest),
+		configFacade.Duration(PathBufferBlockTimeout, DefaultBufferBlockTimeout))
+}
+
+func (*provider) bootRetry(
+	configFacade config.Facade,
+	manager *manager,
+) error {
+	manager.SetRetryPolicy(
+		configFacade.Duration(PathRetryBackoff, DefaultRetryBackoff),
+		configFacade.Duration(PathRetryMaxBackoff, DefaultRetryMaxBackoff),
+		configFacade.Int(PathRetryMaxPending, DefaultRetryMaxPending))
+
+	return nil
+}
+
+func (*provider) bootAsync(
+	configFacade config.Facade,
+	manager *manager,
+) error {
+	manager.SetAsync(
+		configFacade.Bool(PathAsyncEnabled),
+		configFacade.Int(PathAsyncCapacity, DefaultAsyncCapacity),
+		configFacade.Duration(PathAsyncDrainTimeout, DefaultAsyncDrainTimeout))
+
+	return nil
+}
+
+func (*provider) bootFlushMode(
+	configFacade config.Facade,
+	manager *manager,
+) error {
+	return manager.SetFlushMode(
+		configFacade.String(PathFlusherMode, FlusherModePeriodic),
+		LevelFrom(configFacade.Get(PathFlusherFlushOn), None))
+}
+
+func (*provider) bootCaller(
+	configFacade config.Facade,
+	manager *manager,
+) error {
+	manager.SetCallerCapture(
+		configFacade.Bool(PathDefaultCaller, DefaultCaller),
+		LevelFrom(configFacade.Get(PathDefaultStacktraceAt), DefaultStacktraceAt))
+
+	return nil
+}
+
+type bootExtractorsArgs struct {
+	dig.In
+
+	ConfigFacade config.Facade
+	Extractors   []NamedContextExtractor `group:"flam.log.context.extractor"`
+	Manager      *manager
+}
+
+func (*provider) bootExtractors(
+	args bootExtractorsArgs,
+) error {
+	registry := map[string]ContextExtractor{}
+	for _, extractor := range args.Extractors {
+		registry[extractor.Id] = extractor.Extractor
+	}
+
+	var extractors []ContextExtractor
+	for _, value := range args.ConfigFacade.Slice(PathContextExtractors) {
+		id, _ := value.(string)
+		extractor, ok := registry[id]
+		if !ok || extractor == nil {
+			return newErrContextExtractorNotFound(id)
+		}
+		extractors = append(extractors, extractor)
+	}
+	args.Manager.SetExtractors(extractors)
+
+	return nil
+}
+
 func (*provider) bootStreams(
 	configFacade config.Facade,
 	streamFactory steamFactory,
@@ -142,6 +245,16 @@ func (provider *provider) closeFlusher() error {
 	return provider.flusher.Close()
 }
 
+func (*provider) closeManager(
+	configFacade config.Facade,
+	streamFactory steamFactory,
+	manager *manager,
+) error {
+	return manager.Shutdown(
+		configFacade.Duration(PathShutdownTimeout, DefaultShutdownTimeout),
+		streamFactory.Has)
+}
+
 func (*provider) closeStreamFactory(
 	streamFactory steamFactory,
 ) error {
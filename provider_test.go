@@ -412,6 +412,111 @@ func Test_Provider_Close(t *testing.T) {
 		assert.ErrorIs(t, p.(flam.ClosableProvider).Close(container), expectedError)
 	})
 
+	t.Run("should flush the buffer and close the runtime streams", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		container := dig.New()
+		p := NewProvider()
+		require.NoError(t, flamTime.NewProvider().Register(container))
+		require.NoError(t, filesystem.NewProvider().Register(container))
+		require.NoError(t, config.NewProvider().Register(container))
+		require.NoError(t, p.Register(container))
+
+		stm := NewStreamMock(ctrl)
+		gomock.InOrder(
+			stm.EXPECT().Signal(gomock.Any(), Info, "channel", "message", flam.Bag{}).Return(nil),
+			stm.EXPECT().Close().Return(nil),
+		)
+
+		require.NoError(t, config.NewProvider().(flam.BootableProvider).Boot(container))
+		require.NoError(t, p.(flam.BootableProvider).Boot(container))
+		require.NoError(t, container.Invoke(func(facade Facade) {
+			require.NoError(t, facade.AddStream("runtime", stm))
+			require.NoError(t, facade.InfoSignal("channel", "message"))
+		}))
+
+		assert.NoError(t, p.(flam.ClosableProvider).Close(container))
+		assert.NoError(t, container.Invoke(func(facade Facade) {
+			assert.Empty(t, facade.ListStreams())
+		}))
+	})
+
+	t.Run("should report the entries abandoned on shutdown", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		container := dig.New()
+		p := NewProvider()
+		require.NoError(t, flamTime.NewProvider().Register(container))
+		require.NoError(t, filesystem.NewProvider().Register(container))
+		require.NoError(t, config.NewProvider().Register(container))
+		require.NoError(t, p.Register(container))
+
+		expectedError := errors.New("signal error")
+		stm := NewStreamMock(ctrl)
+		gomock.InOrder(
+			stm.EXPECT().Signal(gomock.Any(), Info, "channel", "message1", flam.Bag{}).Return(expectedError),
+			stm.EXPECT().Close().Return(nil),
+		)
+
+		require.NoError(t, config.NewProvider().(flam.BootableProvider).Boot(container))
+		require.NoError(t, p.(flam.BootableProvider).Boot(container))
+		require.NoError(t, container.Invoke(func(facade Facade) {
+			require.NoError(t, facade.AddStream("runtime", stm))
+			require.NoError(t, facade.InfoSignal("channel", "message1"))
+			require.NoError(t, facade.InfoSignal("channel", "message2"))
+		}))
+
+		e := p.(flam.ClosableProvider).Close(container)
+		assert.ErrorIs(t, e, expectedError)
+		assert.ErrorIs(t, e, ErrAbandonedEntries)
+		assert.ErrorContains(t, e, "2")
+	})
+
+	t.Run("should abandon the queued entries after the shutdown timeout", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		config.Defaults = flam.Bag{}
+		_ = config.Defaults.Set(PathAsyncEnabled, true)
+		_ = config.Defaults.Set(PathShutdownTimeout, 10)
+		defer func() { config.Defaults = flam.Bag{} }()
+
+		container := dig.New()
+		p := NewProvider()
+		require.NoError(t, flamTime.NewProvider().Register(container))
+		require.NoError(t, filesystem.NewProvider().Register(container))
+		require.NoError(t, config.NewProvider().Register(container))
+		require.NoError(t, p.Register(container))
+
+		release := make(chan struct{})
+		closed := make(chan struct{})
+		stm := NewStreamMock(ctrl)
+		stm.EXPECT().Signal(gomock.Any(), Info, "channel", "message1", flam.Bag{}).DoAndReturn(
+			func(time.Time, Level, string, string, flam.Bag) error {
+				<-release
+				return nil
+			}).Times(1)
+		stm.EXPECT().Close().DoAndReturn(func() error {
+			close(closed)
+			return nil
+		}).Times(1)
+
+		require.NoError(t, config.NewProvider().(flam.BootableProvider).Boot(container))
+		require.NoError(t, p.(flam.BootableProvider).Boot(container))
+		require.NoError(t, container.Invoke(func(facade Facade) {
+			require.NoError(t, facade.AddStream("runtime", stm))
+			require.NoError(t, facade.InfoSignal("channel", "message1"))
+			require.NoError(t, facade.InfoSignal("channel", "message2"))
+		}))
+
+		assert.ErrorIs(t, p.(flam.ClosableProvider).Close(container), ErrAbandonedEntries)
+
+		close(release)
+		<-closed
+	})
+
 	t.Run("should close the config observer", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
@@ -2,20 +2,21 @@ package log
 
 import (
 	"sort"
+	"time"
 
 	flam "github.com/happyhippyhippo/flam"
 	filesystem "github.com/happyhippyhippo/flam-filesystem"
-	time "github.com/happyhippyhippo/flam-time"
+	flamTime "github.com/happyhippyhippo/flam-time"
 )
 
 type rotatingFileStreamCreator struct {
 	fileStreamCreator
 
-	timeFacade time.Facade
+	timeFacade flamTime.Facade
 }
 
 func newRotatingFileStreamCreator(
-	timeFacade time.Facade,
+	timeFacade flamTime.Facade,
 	fileSystemFacade filesystem.Facade,
 	serializerFactory serializerFactory,
 ) StreamCreator {
@@ -52,7 +53,26 @@ func (creator rotatingFileStreamCreator) Create(
 		return nil, e
 	}
 
-	file, e := newRotatingFileLogWriter(disk, config.String("path"), creator.timeFacade)
+	var location *time.Location
+	if timezone := config.String("timezone"); timezone != "" {
+		if location, e = creator.timeFacade.LoadLocation(timezone); e != nil {
+			return nil, e
+		}
+	}
+
+	file, e := newRotatingFileLogWriter(
+		disk,
+		config.String("path"),
+		creator.timeFacade,
+		rotatingFileLogWriterOptions{
+			period:   config.String("period", RotationDaily),
+			layout:   config.String("layout"),
+			location: location,
+			maxSize:  int64(config.Int("max_size")),
+			maxFiles: config.Int("max_files"),
+			maxAge:   config.Duration("max_age"),
+			compress: config.String("compress"),
+		})
 	if e != nil {
 		return nil, e
 	}
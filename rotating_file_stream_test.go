@@ -1,6 +1,7 @@
 package log
 
 import (
+	"compress/gzip"
 	"errors"
 	"fmt"
 	"io"
@@ -9,6 +10,7 @@ import (
 	"time"
 
 	"github.com/golang/mock/gomock"
+	"github.com/klauspost/compress/zstd"
 	"github.com/spf13/afero"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -141,6 +143,7 @@ func Test_rotatingFileStream(t *testing.T) {
 		expectedError := fmt.Errorf("error")
 		now := time.Now()
 		disk := NewDiskMock(ctrl)
+		disk.EXPECT().Stat("/").Return(nil, os.ErrNotExist).AnyTimes()
 		disk.EXPECT().
 			OpenFile(fmt.Sprintf("/file-%s", now.Format("2006-01-02")), os.O_APPEND|os.O_CREATE|os.O_WRONLY, os.FileMode(0o644)).
 			Return(nil, expectedError)
@@ -544,6 +547,7 @@ func Test_rotatingFileStream(t *testing.T) {
 		now := time.Now()
 		fileName := fmt.Sprintf("/file-%s", now.Format("2006-01-02"))
 		disk := NewDiskMock(ctrl)
+		disk.EXPECT().Stat("/").Return(nil, os.ErrNotExist).AnyTimes()
 		disk.EXPECT().
 			OpenFile(fileName, os.O_APPEND|os.O_CREATE|os.O_WRONLY, os.FileMode(0o644)).
 			Return(file, nil).
@@ -611,6 +615,7 @@ func Test_rotatingFileStream(t *testing.T) {
 		file2.EXPECT().Close().Return(nil).Times(1)
 
 		disk := NewDiskMock(ctrl)
+		disk.EXPECT().Stat("/").Return(nil, os.ErrNotExist).AnyTimes()
 		disk.EXPECT().
 			OpenFile("/file-2021-01-01", os.O_APPEND|os.O_CREATE|os.O_WRONLY, os.FileMode(0o644)).
 			Return(file1, nil).
@@ -663,6 +668,7 @@ func Test_rotatingFileStream(t *testing.T) {
 				"channels":   []any{"channel_1"},
 			}})
 		_ = config.Defaults.Set(PathBoot, true)
+		_ = config.Defaults.Set(PathRetryBackoff, time.Hour)
 		defer func() { config.Defaults = flam.Bag{} }()
 
 		container := dig.New()
@@ -681,6 +687,7 @@ func Test_rotatingFileStream(t *testing.T) {
 
 		expectedError := errors.New("expected error")
 		disk := NewDiskMock(ctrl)
+		disk.EXPECT().Stat("/").Return(nil, os.ErrNotExist).AnyTimes()
 		disk.EXPECT().
 			OpenFile("/file-2021-01-01", os.O_APPEND|os.O_CREATE|os.O_WRONLY, os.FileMode(0o644)).
 			Return(file1, nil).
@@ -707,4 +714,695 @@ func Test_rotatingFileStream(t *testing.T) {
 			assert.ErrorIs(t, facade.Flush(), expectedError)
 		}))
 	})
+
+	t.Run("should rotate to a numbered file when the max size is reached", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		config.Defaults = flam.Bag{}
+		_ = config.Defaults.Set(filesystem.PathDisks, flam.Bag{
+			"mock": flam.Bag{
+				"driver": "mock",
+			}})
+		_ = config.Defaults.Set(PathSerializers, flam.Bag{
+			"string": flam.Bag{
+				"driver": SerializerDriverString,
+			}})
+		_ = config.Defaults.Set(PathStreams, flam.Bag{
+			"my_stream": flam.Bag{
+				"driver":     StreamDriverRotatingFile,
+				"serializer": "string",
+				"level":      "debug",
+				"disk":       "mock",
+				"path":       "/file-%s",
+				"max_size":   60,
+			}})
+		_ = config.Defaults.Set(PathBoot, true)
+		defer func() { config.Defaults = flam.Bag{} }()
+
+		container := dig.New()
+		timeFacade := NewTimeFacadeMock(ctrl)
+		timeFacade.EXPECT().Now().Return(time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)).AnyTimes()
+		require.NoError(t, container.Provide(func() flamTime.Facade { return timeFacade }))
+		require.NoError(t, filesystem.NewProvider().Register(container))
+		require.NoError(t, config.NewProvider().Register(container))
+		require.NoError(t, NewProvider().Register(container))
+
+		disk := afero.NewMemMapFs()
+
+		diskCreatorConfig := flam.Bag{"id": "mock", "driver": "mock"}
+		diskCreator := NewDiskCreatorMock(ctrl)
+		diskCreator.EXPECT().Accept(diskCreatorConfig).Return(true).Times(1)
+		diskCreator.EXPECT().Create(diskCreatorConfig).Return(disk, nil).Times(1)
+
+		require.NoError(t, container.Provide(func() filesystem.DiskCreator {
+			return diskCreator
+		}, dig.Group(filesystem.DiskCreatorGroup)))
+
+		require.NoError(t, config.NewProvider().(flam.BootableProvider).Boot(container))
+		require.NoError(t, NewProvider().(flam.BootableProvider).Boot(container))
+
+		assert.NoError(t, container.Invoke(func(facade Facade) {
+			assert.NoError(t, facade.Broadcast(Info, "message 1"))
+			assert.NoError(t, facade.Broadcast(Info, "message 2"))
+			assert.NoError(t, facade.Broadcast(Info, "message 3"))
+			assert.NoError(t, facade.Flush())
+		}))
+
+		for name, expected := range map[string]string{
+			"/file-2021-01-01":   "message 1",
+			"/file-2021-01-01.1": "message 2",
+			"/file-2021-01-01.2": "message 3",
+		} {
+			data, e := afero.ReadFile(disk, name)
+			require.NoError(t, e)
+			assert.Contains(t, string(data), expected)
+		}
+	})
+
+	t.Run("should remove the oldest files when the max files is exceeded", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		config.Defaults = flam.Bag{}
+		_ = config.Defaults.Set(filesystem.PathDisks, flam.Bag{
+			"mock": flam.Bag{
+				"driver": "mock",
+			}})
+		_ = config.Defaults.Set(PathSerializers, flam.Bag{
+			"string": flam.Bag{
+				"driver": SerializerDriverString,
+			}})
+		_ = config.Defaults.Set(PathStreams, flam.Bag{
+			"my_stream": flam.Bag{
+				"driver":     StreamDriverRotatingFile,
+				"serializer": "string",
+				"level":      "debug",
+				"disk":       "mock",
+				"path":       "/file-%s",
+				"max_files":  2,
+			}})
+		_ = config.Defaults.Set(PathBoot, true)
+		defer func() { config.Defaults = flam.Bag{} }()
+
+		container := dig.New()
+		timeFacade := NewTimeFacadeMock(ctrl)
+		gomock.InOrder(
+			timeFacade.EXPECT().Now().Return(time.Date(2021, 1, 3, 0, 0, 0, 0, time.UTC)),
+			timeFacade.EXPECT().Now().Return(time.Date(2021, 1, 4, 0, 0, 0, 0, time.UTC)).AnyTimes(),
+		)
+		require.NoError(t, container.Provide(func() flamTime.Facade { return timeFacade }))
+		require.NoError(t, filesystem.NewProvider().Register(container))
+		require.NoError(t, config.NewProvider().Register(container))
+		require.NoError(t, NewProvider().Register(container))
+
+		disk := afero.NewMemMapFs()
+		for day := 1; day <= 2; day++ {
+			name := fmt.Sprintf("/file-2021-01-%02d", day)
+			require.NoError(t, afero.WriteFile(disk, name, []byte("old"), 0o644))
+			stamp := time.Date(2021, 1, day, 0, 0, 0, 0, time.UTC)
+			require.NoError(t, disk.Chtimes(name, stamp, stamp))
+		}
+
+		diskCreatorConfig := flam.Bag{"id": "mock", "driver": "mock"}
+		diskCreator := NewDiskCreatorMock(ctrl)
+		diskCreator.EXPECT().Accept(diskCreatorConfig).Return(true).Times(1)
+		diskCreator.EXPECT().Create(diskCreatorConfig).Return(disk, nil).Times(1)
+
+		require.NoError(t, container.Provide(func() filesystem.DiskCreator {
+			return diskCreator
+		}, dig.Group(filesystem.DiskCreatorGroup)))
+
+		require.NoError(t, config.NewProvider().(flam.BootableProvider).Boot(container))
+		require.NoError(t, NewProvider().(flam.BootableProvider).Boot(container))
+
+		assert.NoError(t, container.Invoke(func(facade Facade) {
+			assert.NoError(t, facade.Broadcast(Info, "message"))
+			assert.NoError(t, facade.Flush())
+		}))
+
+		for name, exists := range map[string]bool{
+			"/file-2021-01-01": false,
+			"/file-2021-01-02": false,
+			"/file-2021-01-03": true,
+			"/file-2021-01-04": true,
+		} {
+			found, _ := afero.Exists(disk, name)
+			assert.Equal(t, exists, found, name)
+		}
+	})
+
+	t.Run("should remove the files older than the max age", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		config.Defaults = flam.Bag{}
+		_ = config.Defaults.Set(filesystem.PathDisks, flam.Bag{
+			"mock": flam.Bag{
+				"driver": "mock",
+			}})
+		_ = config.Defaults.Set(PathSerializers, flam.Bag{
+			"string": flam.Bag{
+				"driver": SerializerDriverString,
+			}})
+		_ = config.Defaults.Set(PathStreams, flam.Bag{
+			"my_stream": flam.Bag{
+				"driver":     StreamDriverRotatingFile,
+				"serializer": "string",
+				"level":      "debug",
+				"disk":       "mock",
+				"path":       "/file-%s",
+				"max_age":    48 * time.Hour,
+			}})
+		_ = config.Defaults.Set(PathBoot, true)
+		defer func() { config.Defaults = flam.Bag{} }()
+
+		container := dig.New()
+		timeFacade := NewTimeFacadeMock(ctrl)
+		timeFacade.EXPECT().Now().Return(time.Date(2021, 1, 10, 0, 0, 0, 0, time.UTC)).AnyTimes()
+		require.NoError(t, container.Provide(func() flamTime.Facade { return timeFacade }))
+		require.NoError(t, filesystem.NewProvider().Register(container))
+		require.NoError(t, config.NewProvider().Register(container))
+		require.NoError(t, NewProvider().Register(container))
+
+		disk := afero.NewMemMapFs()
+		for _, day := range []int{1, 9} {
+			name := fmt.Sprintf("/file-2021-01-%02d", day)
+			require.NoError(t, afero.WriteFile(disk, name, []byte("old"), 0o644))
+			stamp := time.Date(2021, 1, day, 0, 0, 0, 0, time.UTC)
+			require.NoError(t, disk.Chtimes(name, stamp, stamp))
+		}
+
+		diskCreatorConfig := flam.Bag{"id": "mock", "driver": "mock"}
+		diskCreator := NewDiskCreatorMock(ctrl)
+		diskCreator.EXPECT().Accept(diskCreatorConfig).Return(true).Times(1)
+		diskCreator.EXPECT().Create(diskCreatorConfig).Return(disk, nil).Times(1)
+
+		require.NoError(t, container.Provide(func() filesystem.DiskCreator {
+			return diskCreator
+		}, dig.Group(filesystem.DiskCreatorGroup)))
+
+		require.NoError(t, config.NewProvider().(flam.BootableProvider).Boot(container))
+		require.NoError(t, NewProvider().(flam.BootableProvider).Boot(container))
+
+		for name, exists := range map[string]bool{
+			"/file-2021-01-01": false,
+			"/file-2021-01-09": true,
+			"/file-2021-01-10": true,
+		} {
+			found, _ := afero.Exists(disk, name)
+			assert.Equal(t, exists, found, name)
+		}
+	})
+
+	t.Run("should compress the rotated file with gzip", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		config.Defaults = flam.Bag{}
+		_ = config.Defaults.Set(filesystem.PathDisks, flam.Bag{
+			"mock": flam.Bag{
+				"driver": "mock",
+			}})
+		_ = config.Defaults.Set(PathSerializers, flam.Bag{
+			"string": flam.Bag{
+				"driver": SerializerDriverString,
+			}})
+		_ = config.Defaults.Set(PathStreams, flam.Bag{
+			"my_stream": flam.Bag{
+				"driver":     StreamDriverRotatingFile,
+				"serializer": "string",
+				"level":      "debug",
+				"disk":       "mock",
+				"path":       "/file-%s",
+				"compress":   CompressionGzip,
+			}})
+		_ = config.Defaults.Set(PathBoot, true)
+		defer func() { config.Defaults = flam.Bag{} }()
+
+		container := dig.New()
+		timeFacade := NewTimeFacadeMock(ctrl)
+		gomock.InOrder(
+			timeFacade.EXPECT().Now().Return(time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)).Times(2),
+			timeFacade.EXPECT().Now().Return(time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC)).AnyTimes(),
+		)
+		provider := NewProvider()
+		require.NoError(t, container.Provide(func() flamTime.Facade { return timeFacade }))
+		require.NoError(t, filesystem.NewProvider().Register(container))
+		require.NoError(t, config.NewProvider().Register(container))
+		require.NoError(t, provider.Register(container))
+
+		disk := afero.NewMemMapFs()
+
+		diskCreatorConfig := flam.Bag{"id": "mock", "driver": "mock"}
+		diskCreator := NewDiskCreatorMock(ctrl)
+		diskCreator.EXPECT().Accept(diskCreatorConfig).Return(true).Times(1)
+		diskCreator.EXPECT().Create(diskCreatorConfig).Return(disk, nil).Times(1)
+
+		require.NoError(t, container.Provide(func() filesystem.DiskCreator {
+			return diskCreator
+		}, dig.Group(filesystem.DiskCreatorGroup)))
+
+		require.NoError(t, config.NewProvider().(flam.BootableProvider).Boot(container))
+		require.NoError(t, provider.(flam.BootableProvider).Boot(container))
+
+		assert.NoError(t, container.Invoke(func(facade Facade) {
+			assert.NoError(t, facade.Broadcast(Info, "message 1"))
+			assert.NoError(t, facade.Broadcast(Info, "message 2"))
+			assert.NoError(t, facade.Flush())
+		}))
+		require.NoError(t, provider.(flam.ClosableProvider).Close(container))
+
+		found, _ := afero.Exists(disk, "/file-2021-01-01")
+		assert.False(t, found)
+
+		file, e := disk.Open("/file-2021-01-01.gz")
+		require.NoError(t, e)
+		defer func() { _ = file.Close() }()
+
+		reader, e := gzip.NewReader(file)
+		require.NoError(t, e)
+		data, e := io.ReadAll(reader)
+		require.NoError(t, e)
+		assert.Contains(t, string(data), "message 1")
+		assert.NotContains(t, string(data), "message 2")
+
+		data, e = afero.ReadFile(disk, "/file-2021-01-02")
+		require.NoError(t, e)
+		assert.Contains(t, string(data), "message 2")
+	})
+
+	t.Run("should compress the rotated file with zstd", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		config.Defaults = flam.Bag{}
+		_ = config.Defaults.Set(filesystem.PathDisks, flam.Bag{
+			"mock": flam.Bag{
+				"driver": "mock",
+			}})
+		_ = config.Defaults.Set(PathSerializers, flam.Bag{
+			"string": flam.Bag{
+				"driver": SerializerDriverString,
+			}})
+		_ = config.Defaults.Set(PathStreams, flam.Bag{
+			"my_stream": flam.Bag{
+				"driver":     StreamDriverRotatingFile,
+				"serializer": "string",
+				"level":      "debug",
+				"disk":       "mock",
+				"path":       "/file-%s",
+				"compress":   CompressionZstd,
+			}})
+		_ = config.Defaults.Set(PathBoot, true)
+		defer func() { config.Defaults = flam.Bag{} }()
+
+		container := dig.New()
+		timeFacade := NewTimeFacadeMock(ctrl)
+		gomock.InOrder(
+			timeFacade.EXPECT().Now().Return(time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)).Times(2),
+			timeFacade.EXPECT().Now().Return(time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC)).AnyTimes(),
+		)
+		provider := NewProvider()
+		require.NoError(t, container.Provide(func() flamTime.Facade { return timeFacade }))
+		require.NoError(t, filesystem.NewProvider().Register(container))
+		require.NoError(t, config.NewProvider().Register(container))
+		require.NoError(t, provider.Register(container))
+
+		disk := afero.NewMemMapFs()
+
+		diskCreatorConfig := flam.Bag{"id": "mock", "driver": "mock"}
+		diskCreator := NewDiskCreatorMock(ctrl)
+		diskCreator.EXPECT().Accept(diskCreatorConfig).Return(true).Times(1)
+		diskCreator.EXPECT().Create(diskCreatorConfig).Return(disk, nil).Times(1)
+
+		require.NoError(t, container.Provide(func() filesystem.DiskCreator {
+			return diskCreator
+		}, dig.Group(filesystem.DiskCreatorGroup)))
+
+		require.NoError(t, config.NewProvider().(flam.BootableProvider).Boot(container))
+		require.NoError(t, provider.(flam.BootableProvider).Boot(container))
+
+		assert.NoError(t, container.Invoke(func(facade Facade) {
+			assert.NoError(t, facade.Broadcast(Info, "message 1"))
+			assert.NoError(t, facade.Broadcast(Info, "message 2"))
+			assert.NoError(t, facade.Flush())
+		}))
+		require.NoError(t, provider.(flam.ClosableProvider).Close(container))
+
+		found, _ := afero.Exists(disk, "/file-2021-01-01")
+		assert.False(t, found)
+
+		file, e := disk.Open("/file-2021-01-01.zst")
+		require.NoError(t, e)
+		defer func() { _ = file.Close() }()
+
+		reader, e := zstd.NewReader(file)
+		require.NoError(t, e)
+		data, e := io.ReadAll(reader)
+		require.NoError(t, e)
+		assert.Contains(t, string(data), "message 1")
+		assert.NotContains(t, string(data), "message 2")
+
+		data, e = afero.ReadFile(disk, "/file-2021-01-02")
+		require.NoError(t, e)
+		assert.Contains(t, string(data), "message 2")
+	})
+
+	t.Run("should compress the rotated files left uncompressed on startup", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		timeFacade := NewTimeFacadeMock(ctrl)
+		timeFacade.EXPECT().Now().Return(time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC)).AnyTimes()
+
+		disk := afero.NewMemMapFs()
+		require.NoError(t, afero.WriteFile(disk, "/file-2020-12-31", []byte("message 1"), 0o644))
+		require.NoError(t, afero.WriteFile(disk, "/file-2021-01-01", []byte("message 2"), 0o644))
+		require.NoError(t, afero.WriteFile(disk, "/file-2020-12-30.gz", []byte("compressed"), 0o644))
+
+		writer, e := newRotatingFileLogWriter(disk, "/file-%s", timeFacade, rotatingFileLogWriterOptions{
+			compress: CompressionGzip,
+		})
+		require.NoError(t, e)
+		require.NoError(t, writer.(io.Closer).Close())
+
+		for name, exists := range map[string]bool{
+			"/file-2020-12-30.gz":    true,
+			"/file-2020-12-30.gz.gz": false,
+			"/file-2020-12-31":       false,
+			"/file-2020-12-31.gz":    true,
+			"/file-2021-01-01":       false,
+			"/file-2021-01-01.gz":    true,
+			"/file-2021-01-02":       true,
+		} {
+			found, _ := afero.Exists(disk, name)
+			assert.Equal(t, exists, found, name)
+		}
+	})
+
+	t.Run("should resume after the last rotated file on restart", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		timeFacade := NewTimeFacadeMock(ctrl)
+		timeFacade.EXPECT().Now().Return(time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)).AnyTimes()
+
+		disk := afero.NewMemMapFs()
+		options := rotatingFileLogWriterOptions{
+			maxSize:  10,
+			compress: CompressionGzip,
+		}
+
+		writer, e := newRotatingFileLogWriter(disk, "/app-%s.log", timeFacade, options)
+		require.NoError(t, e)
+		for _, message := range []string{"message 1", "message 2", "message 3"} {
+			_, e := writer.Write([]byte(message + "\n"))
+			require.NoError(t, e)
+		}
+		require.NoError(t, writer.(io.Closer).Close())
+
+		writer, e = newRotatingFileLogWriter(disk, "/app-%s.log", timeFacade, options)
+		require.NoError(t, e)
+		_, e = writer.Write([]byte("message 4\n"))
+		require.NoError(t, e)
+		require.NoError(t, writer.(io.Closer).Close())
+
+		for name, expected := range map[string]string{
+			"/app-2021-01-01.log.gz":   "message 1",
+			"/app-2021-01-01.log.1.gz": "message 2",
+			"/app-2021-01-01.log.2.gz": "message 3",
+		} {
+			file, e := disk.Open(name)
+			require.NoError(t, e)
+			reader, e := gzip.NewReader(file)
+			require.NoError(t, e)
+			data, e := io.ReadAll(reader)
+			require.NoError(t, e)
+			assert.Equal(t, expected+"\n", string(data), name)
+			_ = file.Close()
+		}
+
+		data, e := afero.ReadFile(disk, "/app-2021-01-01.log.3")
+		require.NoError(t, e)
+		assert.Equal(t, "message 4\n", string(data))
+	})
+
+	t.Run("should only select the files of its own stream on a shared directory", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		timeFacade := NewTimeFacadeMock(ctrl)
+		timeFacade.EXPECT().Now().Return(time.Date(2021, 1, 4, 0, 0, 0, 0, time.UTC)).AnyTimes()
+
+		disk := afero.NewMemMapFs()
+		for day := 1; day <= 3; day++ {
+			stamp := time.Date(2021, 1, day, 0, 0, 0, 0, time.UTC)
+			for _, name := range []string{
+				fmt.Sprintf("/log/app-2021-01-%02d.log", day),
+				fmt.Sprintf("/log/app-errors-2021-01-%02d.log", day),
+			} {
+				require.NoError(t, afero.WriteFile(disk, name, []byte("old"), 0o644))
+				require.NoError(t, disk.Chtimes(name, stamp, stamp))
+			}
+		}
+
+		writer, e := newRotatingFileLogWriter(disk, "/log/app-%s.log", timeFacade, rotatingFileLogWriterOptions{
+			maxFiles: 2,
+			compress: CompressionGzip,
+		})
+		require.NoError(t, e)
+		require.NoError(t, writer.(io.Closer).Close())
+
+		for name, exists := range map[string]bool{
+			"/log/app-2021-01-01.log":           false,
+			"/log/app-2021-01-02.log":           false,
+			"/log/app-2021-01-03.log":           false,
+			"/log/app-2021-01-03.log.gz":        true,
+			"/log/app-2021-01-04.log":           true,
+			"/log/app-errors-2021-01-01.log":    true,
+			"/log/app-errors-2021-01-02.log":    true,
+			"/log/app-errors-2021-01-03.log":    true,
+			"/log/app-errors-2021-01-01.log.gz": false,
+		} {
+			found, _ := afero.Exists(disk, name)
+			assert.Equal(t, exists, found, name)
+		}
+	})
+
+	t.Run("should return an error on unknown compression", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		config.Defaults = flam.Bag{}
+		_ = config.Defaults.Set(filesystem.PathDisks, flam.Bag{
+			"mock": flam.Bag{
+				"driver": "mock",
+			}})
+		_ = config.Defaults.Set(PathSerializers, flam.Bag{
+			"string": flam.Bag{
+				"driver": SerializerDriverString,
+			}})
+		_ = config.Defaults.Set(PathStreams, flam.Bag{
+			"my_stream": flam.Bag{
+				"driver":     StreamDriverRotatingFile,
+				"serializer": "string",
+				"disk":       "mock",
+				"path":       "/file-%s",
+				"compress":   "invalid",
+			}})
+		_ = config.Defaults.Set(PathBoot, true)
+		defer func() { config.Defaults = flam.Bag{} }()
+
+		container := dig.New()
+		require.NoError(t, flamTime.NewProvider().Register(container))
+		require.NoError(t, filesystem.NewProvider().Register(container))
+		require.NoError(t, config.NewProvider().Register(container))
+		require.NoError(t, NewProvider().Register(container))
+
+		diskCreatorConfig := flam.Bag{"id": "mock", "driver": "mock"}
+		diskCreator := NewDiskCreatorMock(ctrl)
+		diskCreator.EXPECT().Accept(diskCreatorConfig).Return(true).Times(1)
+		diskCreator.EXPECT().Create(diskCreatorConfig).Return(afero.NewMemMapFs(), nil).Times(1)
+
+		require.NoError(t, container.Provide(func() filesystem.DiskCreator {
+			return diskCreator
+		}, dig.Group(filesystem.DiskCreatorGroup)))
+
+		require.NoError(t, config.NewProvider().(flam.BootableProvider).Boot(container))
+
+		assert.ErrorIs(
+			t,
+			NewProvider().(flam.BootableProvider).Boot(container),
+			ErrUnknownCompression)
+	})
+
+	t.Run("should rotate hourly using the path tokens", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		config.Defaults = flam.Bag{}
+		_ = config.Defaults.Set(filesystem.PathDisks, flam.Bag{
+			"mock": flam.Bag{
+				"driver": "mock",
+			}})
+		_ = config.Defaults.Set(PathSerializers, flam.Bag{
+			"string": flam.Bag{
+				"driver": SerializerDriverString,
+			}})
+		_ = config.Defaults.Set(PathStreams, flam.Bag{
+			"my_stream": flam.Bag{
+				"driver":     StreamDriverRotatingFile,
+				"serializer": "string",
+				"level":      "debug",
+				"disk":       "mock",
+				"path":       "/file-{date}-{hour}.log",
+				"period":     RotationHourly,
+			}})
+		_ = config.Defaults.Set(PathBoot, true)
+		defer func() { config.Defaults = flam.Bag{} }()
+
+		container := dig.New()
+		timeFacade := NewTimeFacadeMock(ctrl)
+		gomock.InOrder(
+			timeFacade.EXPECT().Now().Return(time.Date(2021, 1, 1, 10, 15, 0, 0, time.UTC)),
+			timeFacade.EXPECT().Now().Return(time.Date(2021, 1, 1, 10, 45, 0, 0, time.UTC)),
+			timeFacade.EXPECT().Now().Return(time.Date(2021, 1, 1, 11, 5, 0, 0, time.UTC)),
+		)
+		require.NoError(t, container.Provide(func() flamTime.Facade { return timeFacade }))
+		require.NoError(t, filesystem.NewProvider().Register(container))
+		require.NoError(t, config.NewProvider().Register(container))
+		require.NoError(t, NewProvider().Register(container))
+
+		disk := afero.NewMemMapFs()
+
+		diskCreatorConfig := flam.Bag{"id": "mock", "driver": "mock"}
+		diskCreator := NewDiskCreatorMock(ctrl)
+		diskCreator.EXPECT().Accept(diskCreatorConfig).Return(true).Times(1)
+		diskCreator.EXPECT().Create(diskCreatorConfig).Return(disk, nil).Times(1)
+
+		require.NoError(t, container.Provide(func() filesystem.DiskCreator {
+			return diskCreator
+		}, dig.Group(filesystem.DiskCreatorGroup)))
+
+		require.NoError(t, config.NewProvider().(flam.BootableProvider).Boot(container))
+		require.NoError(t, NewProvider().(flam.BootableProvider).Boot(container))
+
+		assert.NoError(t, container.Invoke(func(facade Facade) {
+			assert.NoError(t, facade.Broadcast(Info, "message 1"))
+			assert.NoError(t, facade.Broadcast(Info, "message 2"))
+			assert.NoError(t, facade.Flush())
+		}))
+
+		data, e := afero.ReadFile(disk, "/file-2021-01-01-10.log")
+		require.NoError(t, e)
+		assert.Contains(t, string(data), "message 1")
+
+		data, e = afero.ReadFile(disk, "/file-2021-01-01-11.log")
+		require.NoError(t, e)
+		assert.Contains(t, string(data), "message 2")
+	})
+
+	t.Run("should name the files with the layout in the configured timezone", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		config.Defaults = flam.Bag{}
+		_ = config.Defaults.Set(filesystem.PathDisks, flam.Bag{
+			"mock": flam.Bag{
+				"driver": "mock",
+			}})
+		_ = config.Defaults.Set(PathSerializers, flam.Bag{
+			"string": flam.Bag{
+				"driver": SerializerDriverString,
+			}})
+		_ = config.Defaults.Set(PathStreams, flam.Bag{
+			"my_stream": flam.Bag{
+				"driver":     StreamDriverRotatingFile,
+				"serializer": "string",
+				"level":      "debug",
+				"disk":       "mock",
+				"path":       "/file-%s.log",
+				"period":     RotationMonthly,
+				"layout":     "2006.01",
+				"timezone":   "custom",
+			}})
+		_ = config.Defaults.Set(PathBoot, true)
+		defer func() { config.Defaults = flam.Bag{} }()
+
+		container := dig.New()
+		timeFacade := NewTimeFacadeMock(ctrl)
+		timeFacade.EXPECT().LoadLocation("custom").Return(time.FixedZone("custom", 5*3600), nil).Times(1)
+		timeFacade.EXPECT().Now().Return(time.Date(2021, 1, 31, 22, 0, 0, 0, time.UTC)).Times(1)
+		require.NoError(t, container.Provide(func() flamTime.Facade { return timeFacade }))
+		require.NoError(t, filesystem.NewProvider().Register(container))
+		require.NoError(t, config.NewProvider().Register(container))
+		require.NoError(t, NewProvider().Register(container))
+
+		disk := afero.NewMemMapFs()
+
+		diskCreatorConfig := flam.Bag{"id": "mock", "driver": "mock"}
+		diskCreator := NewDiskCreatorMock(ctrl)
+		diskCreator.EXPECT().Accept(diskCreatorConfig).Return(true).Times(1)
+		diskCreator.EXPECT().Create(diskCreatorConfig).Return(disk, nil).Times(1)
+
+		require.NoError(t, container.Provide(func() filesystem.DiskCreator {
+			return diskCreator
+		}, dig.Group(filesystem.DiskCreatorGroup)))
+
+		require.NoError(t, config.NewProvider().(flam.BootableProvider).Boot(container))
+		require.NoError(t, NewProvider().(flam.BootableProvider).Boot(container))
+
+		found, _ := afero.Exists(disk, "/file-2021.02.log")
+		assert.True(t, found)
+	})
+
+	t.Run("should return an error on unknown rotation period", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		config.Defaults = flam.Bag{}
+		_ = config.Defaults.Set(filesystem.PathDisks, flam.Bag{
+			"mock": flam.Bag{
+				"driver": "mock",
+			}})
+		_ = config.Defaults.Set(PathSerializers, flam.Bag{
+			"string": flam.Bag{
+				"driver": SerializerDriverString,
+			}})
+		_ = config.Defaults.Set(PathStreams, flam.Bag{
+			"my_stream": flam.Bag{
+				"driver":     StreamDriverRotatingFile,
+				"serializer": "string",
+				"disk":       "mock",
+				"path":       "/file-%s",
+				"period":     "invalid",
+			}})
+		_ = config.Defaults.Set(PathBoot, true)
+		defer func() { config.Defaults = flam.Bag{} }()
+
+		container := dig.New()
+		require.NoError(t, flamTime.NewProvider().Register(container))
+		require.NoError(t, filesystem.NewProvider().Register(container))
+		require.NoError(t, config.NewProvider().Register(container))
+		require.NoError(t, NewProvider().Register(container))
+
+		disk := afero.NewMemMapFs()
+
+		diskCreatorConfig := flam.Bag{"id": "mock", "driver": "mock"}
+		diskCreator := NewDiskCreatorMock(ctrl)
+		diskCreator.EXPECT().Accept(diskCreatorConfig).Return(true).Times(1)
+		diskCreator.EXPECT().Create(diskCreatorConfig).Return(disk, nil).Times(1)
+
+		require.NoError(t, container.Provide(func() filesystem.DiskCreator {
+			return diskCreator
+		}, dig.Group(filesystem.DiskCreatorGroup)))
+
+		require.NoError(t, config.NewProvider().(flam.BootableProvider).Boot(container))
+
+		assert.ErrorIs(
+			t,
+			NewProvider().(flam.BootableProvider).Boot(container),
+			ErrUnknownRotationPeriod)
+	})
 }
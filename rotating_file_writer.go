@@ -4,22 +4,60 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
+	"unicode"
+
+	"github.com/spf13/afero"
 
 	filesystem "github.com/happyhippyhippo/flam-filesystem"
 	flamTime "github.com/happyhippyhippo/flam-time"
 )
 
+var rotationLayouts = map[string]string{
+	RotationHourly:  "2006-01-02T15",
+	RotationDaily:   "2006-01-02",
+	RotationWeekly:  "2006-01-02",
+	RotationMonthly: "2006-01",
+}
+
+var rotationTokens = []string{"{date}", "{hour}", "{year}", "{month}", "{day}", "{week}", "{seq}"}
+
+var rotationStampParts = regexp.MustCompile(`[0-9]+|[A-Za-z]+|[^0-9A-Za-z]+`)
+
+type rotatingFileLogWriterOptions struct {
+	period   string
+	layout   string
+	location *time.Location
+	maxSize  int64
+	maxFiles int
+	maxAge   time.Duration
+	compress string
+}
+
+type rotatedFile struct {
+	name       string
+	base       string
+	seq        int
+	compressed bool
+}
+
 type rotatingFileLogWriter struct {
 	lock       sync.Locker
 	disk       filesystem.Disk
 	file       filesystem.File
 	path       string
 	timeFacade flamTime.Facade
-	year       int
-	month      time.Month
-	day        int
+	options    rotatingFileLogWriterOptions
+	compressor *logCompressor
+	matcher    *regexp.Regexp
+	start      time.Time
+	seq        int
+	size       int64
 	current    string
 }
 
@@ -27,17 +65,48 @@ func newRotatingFileLogWriter(
 	disk filesystem.Disk,
 	path string,
 	timeFacade flamTime.Facade,
+	options rotatingFileLogWriterOptions,
 ) (io.Writer, error) {
 	writer := &rotatingFileLogWriter{
 		lock:       &sync.Mutex{},
 		disk:       disk,
 		path:       path,
 		timeFacade: timeFacade,
+		options:    options,
+	}
+
+	if writer.options.period == "" {
+		writer.options.period = RotationDaily
+	}
+
+	layout, ok := rotationLayouts[writer.options.period]
+	if !ok {
+		return nil, newErrUnknownRotationPeriod(writer.options.period)
+	}
+
+	if writer.options.layout == "" {
+		writer.options.layout = layout
+	}
+
+	matcher, e := regexp.Compile(writer.pattern())
+	if e != nil {
+		return nil, e
+	}
+	writer.matcher = matcher
+
+	if options.compress != "" {
+		compressor, e := newLogCompressor(disk, options.compress)
+		if e != nil {
+			return nil, e
+		}
+		writer.compressor = compressor
 	}
 
-	if e := writer.rotate(timeFacade.Now()); e != nil {
+	if e := writer.rotate(writer.now()); e != nil {
+		writer.closeCompressor()
 		return nil, e
 	}
+	writer.queueUncompressed()
 
 	return writer, nil
 }
@@ -48,43 +117,373 @@ func (writer *rotatingFileLogWriter) Write(
 	writer.lock.Lock()
 	defer writer.lock.Unlock()
 
-	if e := writer.checkRotation(); e != nil {
+	if e := writer.checkRotation(int64(len(output))); e != nil {
 		return 0, e
 	}
 
-	return writer.file.Write(output)
+	n, e := writer.file.Write(output)
+	writer.size += int64(n)
+
+	return n, e
 }
 
 func (writer *rotatingFileLogWriter) Close() error {
-	return writer.file.Close()
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+
+	e := writer.file.Close()
+	writer.closeCompressor()
+
+	return e
 }
 
-func (writer *rotatingFileLogWriter) checkRotation() error {
-	now := writer.timeFacade.Now()
-	if now.Day() != writer.day || now.Month() != writer.month || now.Year() != writer.year {
+func (writer *rotatingFileLogWriter) closeCompressor() {
+	if writer.compressor != nil {
+		writer.compressor.Close()
+	}
+}
+
+func (writer *rotatingFileLogWriter) queueUncompressed() {
+	if writer.compressor == nil {
+		return
+	}
+
+	files, _ := writer.rotated()
+	for _, file := range files {
+		if !file.compressed && !writer.isActive(file) {
+			writer.compressor.Queue(file.name)
+		}
+	}
+}
+
+func (writer *rotatingFileLogWriter) checkRotation(
+	length int64,
+) error {
+	now := writer.now()
+	if !writer.periodStart(now).Equal(writer.start) {
 		return writer.rotate(now)
 	}
 
+	for writer.exceedsSize(length) {
+		if e := writer.open(now, writer.nextSeq(now, false)); e != nil {
+			return e
+		}
+	}
+
 	return nil
 }
 
+func (writer *rotatingFileLogWriter) now() time.Time {
+	now := writer.timeFacade.Now()
+	if writer.options.location != nil {
+		now = now.In(writer.options.location)
+	}
+
+	return now
+}
+
+func (writer *rotatingFileLogWriter) periodStart(
+	now time.Time,
+) time.Time {
+	year, month, day := now.Date()
+	switch writer.options.period {
+	case RotationHourly:
+		return time.Date(year, month, day, now.Hour(), 0, 0, 0, now.Location())
+	case RotationWeekly:
+		offset := (int(now.Weekday()) + 6) % 7
+		return time.Date(year, month, day-offset, 0, 0, 0, 0, now.Location())
+	case RotationMonthly:
+		return time.Date(year, month, 1, 0, 0, 0, 0, now.Location())
+	default:
+		return time.Date(year, month, day, 0, 0, 0, 0, now.Location())
+	}
+}
+
+func (writer *rotatingFileLogWriter) exceedsSize(
+	length int64,
+) bool {
+	return writer.options.maxSize > 0 &&
+		writer.size > 0 &&
+		writer.size+length > writer.options.maxSize
+}
+
 func (writer *rotatingFileLogWriter) rotate(
 	now time.Time,
 ) error {
-	writer.year = now.Year()
-	writer.month = now.Month()
-	writer.day = now.Day()
-	writer.current = fmt.Sprintf(writer.path, now.Format("2006-01-02"))
+	writer.start = writer.periodStart(now)
+
+	return writer.open(now, writer.nextSeq(now, true))
+}
+
+func (writer *rotatingFileLogWriter) nextSeq(
+	now time.Time,
+	resume bool,
+) int {
+	files, _ := writer.rotated()
+
+	var last *rotatedFile
+	for _, file := range files {
+		if file.base != writer.fileName(now, file.seq) {
+			continue
+		}
+		if last == nil || file.seq > last.seq || (file.seq == last.seq && file.compressed) {
+			last = &file
+		}
+	}
+
+	switch {
+	case last == nil:
+		return 0
+	case resume && !last.compressed && (writer.compressor == nil || !writer.compressor.IsPending(last.name)):
+		return last.seq
+	default:
+		return last.seq + 1
+	}
+}
+
+func (writer *rotatingFileLogWriter) isActive(
+	file rotatedFile,
+) bool {
+	return file.base == writer.current ||
+		(file.seq >= writer.seq && file.base == writer.fileName(writer.start, file.seq))
+}
+
+func (writer *rotatingFileLogWriter) open(
+	now time.Time,
+	seq int,
+) error {
+	current := writer.fileName(now, seq)
 
-	fp, e := writer.disk.OpenFile(writer.current, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	fp, e := writer.disk.OpenFile(current, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
 	if e != nil {
 		return e
 	}
 
+	size := int64(0)
+	if writer.options.maxSize > 0 {
+		if info, e := fp.Stat(); e == nil {
+			size = info.Size()
+		}
+	}
+
 	if writer.file != nil {
 		_ = writer.file.Close()
+		if writer.compressor != nil && writer.current != current {
+			writer.compressor.Queue(writer.current)
+		}
 	}
 	writer.file = fp
+	writer.current = current
+	writer.seq = seq
+	writer.size = size
+
+	return writer.cleanup(now)
+}
+
+func (writer *rotatingFileLogWriter) fileName(
+	now time.Time,
+	seq int,
+) string {
+	start := writer.periodStart(now)
+
+	stamp := writer.options.layout
+	if !strings.Contains(stamp, "{") {
+		stamp = start.Format(stamp)
+	}
+
+	name := strings.ReplaceAll(writer.path, "%s", stamp)
+	if strings.Contains(name, "{") {
+		_, week := start.ISOWeek()
+		name = strings.NewReplacer(
+			"{date}", start.Format("2006-01-02"),
+			"{hour}", start.Format("15"),
+			"{year}", start.Format("2006"),
+			"{month}", start.Format("01"),
+			"{day}", start.Format("02"),
+			"{week}", fmt.Sprintf("%02d", week),
+			"{seq}", strconv.Itoa(seq),
+		).Replace(name)
+	}
+
+	if seq > 0 && !writer.hasSeqToken() {
+		name = fmt.Sprintf("%s.%d", name, seq)
+	}
+
+	return name
+}
+
+func (writer *rotatingFileLogWriter) hasSeqToken() bool {
+	return strings.Contains(writer.path, "{seq}") ||
+		(strings.Contains(writer.path, "%s") && strings.Contains(writer.options.layout, "{seq}"))
+}
+
+func (writer *rotatingFileLogWriter) globPattern() string {
+	pattern := strings.ReplaceAll(writer.path, "%s", "*")
+	for _, token := range rotationTokens {
+		pattern = strings.ReplaceAll(pattern, token, "*")
+	}
+
+	return pattern + "*"
+}
+
+func (writer *rotatingFileLogWriter) pattern() string {
+	seq := false
+	var builder strings.Builder
+	var expand func(template string)
+	expand = func(template string) {
+		for len(template) > 0 {
+			switch {
+			case strings.HasPrefix(template, "%s"):
+				if strings.Contains(writer.options.layout, "{") {
+					expand(writer.options.layout)
+				} else {
+					builder.WriteString(stampPattern(writer.options.layout))
+				}
+				template = template[2:]
+			case strings.HasPrefix(template, "{seq}") && !seq:
+				builder.WriteString(`(?P<seq>\d+)`)
+				seq = true
+				template = template[5:]
+			default:
+				token := ""
+				for _, candidate := range rotationTokens {
+					if strings.HasPrefix(template, candidate) {
+						token = candidate
+						break
+					}
+				}
+				if token != "" {
+					builder.WriteString(`\d+`)
+					template = template[len(token):]
+					continue
+				}
+				builder.WriteString(regexp.QuoteMeta(template[:1]))
+				template = template[1:]
+			}
+		}
+	}
+
+	builder.WriteString("^")
+	expand(writer.path)
+	if !seq {
+		builder.WriteString(`(?:\.(?P<seq>\d+))?`)
+	}
+	builder.WriteString(`(?P<ext>`)
+	for i, extension := range compressedExtensions {
+		if i > 0 {
+			builder.WriteString("|")
+		}
+		builder.WriteString(regexp.QuoteMeta(extension))
+	}
+	builder.WriteString(`)?$`)
+
+	return builder.String()
+}
+
+func stampPattern(
+	layout string,
+) string {
+	sample := time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC).Format(layout)
+
+	var builder strings.Builder
+	for _, part := range rotationStampParts.FindAllString(sample, -1) {
+		switch {
+		case unicode.IsDigit(rune(part[0])):
+			builder.WriteString(`\d+`)
+		case unicode.IsLetter(rune(part[0])):
+			builder.WriteString(`[A-Za-z]+`)
+		default:
+			builder.WriteString(regexp.QuoteMeta(part))
+		}
+	}
+
+	return builder.String()
+}
+
+func (writer *rotatingFileLogWriter) rotated() ([]rotatedFile, error) {
+	matches, e := afero.Glob(writer.disk, writer.globPattern())
+	if e != nil {
+		return nil, e
+	}
+
+	var files []rotatedFile
+	for _, match := range matches {
+		groups := writer.matcher.FindStringSubmatch(match)
+		if groups == nil {
+			continue
+		}
+
+		if info, e := writer.disk.Stat(match); e != nil || info.IsDir() {
+			continue
+		}
+
+		extension := groups[writer.matcher.SubexpIndex("ext")]
+		seq, _ := strconv.Atoi(groups[writer.matcher.SubexpIndex("seq")])
+		files = append(files, rotatedFile{
+			name:       match,
+			base:       strings.TrimSuffix(match, extension),
+			seq:        seq,
+			compressed: extension != "",
+		})
+	}
+
+	return files, nil
+}
+
+func (writer *rotatingFileLogWriter) cleanup(
+	now time.Time,
+) error {
+	if writer.options.maxFiles <= 0 && writer.options.maxAge <= 0 {
+		return nil
+	}
+
+	files, e := writer.rotated()
+	if e != nil {
+		return e
+	}
+
+	type candidate struct {
+		name    string
+		modTime time.Time
+	}
+
+	var candidates []candidate
+	for _, file := range files {
+		if writer.isActive(file) || (writer.compressor != nil && writer.compressor.IsPending(file.name)) {
+			continue
+		}
+
+		info, e := writer.disk.Stat(file.name)
+		if e != nil {
+			continue
+		}
+
+		if writer.options.maxAge > 0 && now.Sub(info.ModTime()) > writer.options.maxAge {
+			if e := writer.disk.Remove(file.name); e != nil {
+				return e
+			}
+			continue
+		}
+
+		candidates = append(candidates, candidate{name: file.name, modTime: info.ModTime()})
+	}
+
+	if writer.options.maxFiles <= 0 || len(candidates) < writer.options.maxFiles {
+		return nil
+	}
+
+	slices.SortFunc(candidates, func(a, b candidate) int {
+		if c := b.modTime.Compare(a.modTime); c != 0 {
+			return c
+		}
+		return strings.Compare(b.name, a.name)
+	})
+
+	for _, c := range candidates[writer.options.maxFiles-1:] {
+		if e := writer.disk.Remove(c.name); e != nil {
+			return e
+		}
+	}
 
 	return nil
 }
@@ -1,13 +1,135 @@
 package log
 
 import (
-	"time"
+	"io"
+	"slices"
 
 	flam "github.com/happyhippyhippo/flam"
 )
 
+var reservedKeys = []string{"time", "level", "channel", "message", "msg"}
+
 type Serializer interface {
 	Close() error
 
-	Serialize(timestamp time.Time, level Level, message string, ctx flam.Bag) string
+	Serialize(writer io.Writer, entry Entry) error
+}
+
+func isReservedKey(
+	key string,
+) bool {
+	return slices.Contains(reservedKeys, key)
+}
+
+func renameContextKey(
+	key string,
+	ctx flam.Bag,
+	reserved func(key string) bool,
+) string {
+	if !reserved(key) {
+		return key
+	}
+
+	for renamed := "ctx." + key; ; renamed = "ctx." + renamed {
+		if _, taken := ctx[renamed]; !taken && !ctx.Has(renamed) && !reserved(renamed) {
+			return renamed
+		}
+	}
+}
+
+type contextField struct {
+	key   string
+	value any
+}
+
+func flattenContext(
+	prefix string,
+	bag flam.Bag,
+) []contextField {
+	var fields []contextField
+	walkContext(prefix, bag, nil, func(path string, value any) {
+		fields = append(fields, contextField{key: path, value: value})
+	})
+
+	return fields
+}
+
+func walkContext(
+	prefix string,
+	bag flam.Bag,
+	key func(prefix, key string) string,
+	visit func(path string, value any),
+) {
+	keys := make([]string, 0, len(bag))
+	for k := range bag {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	for _, k := range keys {
+		path := k
+		switch {
+		case key != nil:
+			path = key(prefix, k)
+		case prefix != "":
+			path = prefix + "." + k
+		}
+
+		switch value := bag[k].(type) {
+		case flam.Bag:
+			walkContext(path, value, key, visit)
+		case *flam.Bag:
+			walkContext(path, *value, key, visit)
+		default:
+			visit(path, value)
+		}
+	}
+}
+
+type serializationWriter struct {
+	writer io.Writer
+	e      error
+}
+
+func (writer *serializationWriter) Write(
+	data []byte,
+) (int, error) {
+	if writer.e != nil {
+		return 0, writer.e
+	}
+
+	var n int
+	n, writer.e = writer.writer.Write(data)
+
+	return n, writer.e
+}
+
+func (writer *serializationWriter) WriteString(
+	data string,
+) (int, error) {
+	if writer.e != nil {
+		return 0, writer.e
+	}
+
+	var n int
+	n, writer.e = io.WriteString(writer.writer, data)
+
+	return n, writer.e
+}
+
+func (writer *serializationWriter) WriteByte(
+	data byte,
+) error {
+	if writer.e != nil {
+		return writer.e
+	}
+
+	if byteWriter, ok := writer.writer.(io.ByteWriter); ok {
+		writer.e = byteWriter.WriteByte(data)
+		return writer.e
+	}
+
+	_, e := writer.Write([]byte{data})
+
+	return e
 }
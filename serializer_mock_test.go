@@ -1,12 +1,10 @@
 package log
 
 import (
+	io "io"
 	reflect "reflect"
-	time "time"
 
 	gomock "github.com/golang/mock/gomock"
-
-	flam "github.com/happyhippyhippo/flam"
 )
 
 type SerializerMock struct {
@@ -40,14 +38,14 @@ func (mr *SerializerMockRecorder) Close() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*SerializerMock)(nil).Close))
 }
 
-func (m *SerializerMock) Serialize(timestamp time.Time, level Level, message string, ctx flam.Bag) string {
+func (m *SerializerMock) Serialize(writer io.Writer, entry Entry) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Serialize", timestamp, level, message, ctx)
-	ret0, _ := ret[0].(string)
+	ret := m.ctrl.Call(m, "Serialize", writer, entry)
+	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-func (mr *SerializerMockRecorder) Serialize(timestamp, level, message, ctx interface{}) *gomock.Call {
+func (mr *SerializerMockRecorder) Serialize(writer, entry interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Serialize", reflect.TypeOf((*SerializerMock)(nil).Serialize), timestamp, level, message, ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Serialize", reflect.TypeOf((*SerializerMock)(nil).Serialize), writer, entry)
 }
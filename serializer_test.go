@@ -0,0 +1,45 @@
+package log
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	flam "github.com/happyhippyhippo/flam"
+)
+
+func serialize(
+	t *testing.T,
+	serializer Serializer,
+	entry Entry,
+) string {
+	buffer := &bytes.Buffer{}
+	require.NoError(t, serializer.Serialize(buffer, entry))
+
+	return buffer.String()
+}
+
+func newDefaultJsonSerializer(
+	t *testing.T,
+) Serializer {
+	serializer, e := newJsonSerializer(jsonSerializerOptions{})
+	require.NoError(t, e)
+
+	return serializer
+}
+
+func Test_stream_NonMutating(t *testing.T) {
+	t.Run("should not change the context bag given to the stream", func(t *testing.T) {
+		buffer := &bytes.Buffer{}
+		target := newStream(Debug, []string{"*"}, newDefaultJsonSerializer(t), buffer, false)
+
+		ctx := flam.Bag{"key": "value"}
+		require.NoError(t, target.Signal(time.Now(), Info, "channel", "message", ctx))
+
+		assert.Equal(t, flam.Bag{"key": "value"}, ctx)
+		assert.Contains(t, buffer.String(), `"channel":"channel"`)
+	})
+}
@@ -0,0 +1,202 @@
+package log
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	flam "github.com/happyhippyhippo/flam"
+)
+
+type pcSignaler interface {
+	signalAt(ctx context.Context, timestamp time.Time, pc uintptr, level Level, channel, message string, bag flam.Bag) error
+}
+
+type slogHandler struct {
+	facade           Facade
+	channelAttribute string
+	channel          string
+	attrs            flam.Bag
+	groups           []string
+}
+
+func NewSlogHandler(
+	facade Facade,
+	channelAttribute string,
+) slog.Handler {
+	return &slogHandler{
+		facade:           facade,
+		channelAttribute: channelAttribute,
+		attrs:            flam.Bag{},
+	}
+}
+
+func (handler *slogHandler) Enabled(
+	_ context.Context,
+	level slog.Level,
+) bool {
+	return handler.facade.IsEnabled(levelFromSlog(level), handler.channel)
+}
+
+func (handler *slogHandler) Handle(
+	ctx context.Context,
+	record slog.Record,
+) error {
+	channel := handler.channel
+	bag := handler.attrs.Clone()
+	attrs := flam.Bag{}
+
+	record.Attrs(func(attr slog.Attr) bool {
+		if value, ok := handler.channelOf(attr); ok {
+			channel = value
+			return true
+		}
+
+		slogAttr(attrs, attr)
+		return true
+	})
+	if len(attrs) != 0 {
+		slogMerge(slogGroup(bag, handler.groups), attrs)
+	}
+
+	level := levelFromSlog(record.Level)
+	if signaler, ok := handler.facade.(pcSignaler); ok {
+		return signaler.signalAt(ctx, record.Time, record.PC, level, channel, record.Message, bag)
+	}
+	if channel == "" {
+		return handler.facade.BroadcastCtx(ctx, level, record.Message, bag)
+	}
+
+	return handler.facade.SignalCtx(ctx, level, channel, record.Message, bag)
+}
+
+func (handler *slogHandler) WithAttrs(
+	attrs []slog.Attr,
+) slog.Handler {
+	if len(attrs) == 0 {
+		return handler
+	}
+
+	clone := *handler
+	clone.attrs = handler.attrs.Clone()
+	resolved := flam.Bag{}
+	for _, attr := range attrs {
+		if value, ok := handler.channelOf(attr); ok {
+			clone.channel = value
+			continue
+		}
+
+		slogAttr(resolved, attr)
+	}
+	if len(resolved) != 0 {
+		slogMerge(slogGroup(clone.attrs, clone.groups), resolved)
+	}
+
+	return &clone
+}
+
+func (handler *slogHandler) WithGroup(
+	name string,
+) slog.Handler {
+	if name == "" {
+		return handler
+	}
+
+	clone := *handler
+	clone.groups = append(handler.groups[:len(handler.groups):len(handler.groups)], name)
+
+	return &clone
+}
+
+func (handler *slogHandler) channelOf(
+	attr slog.Attr,
+) (string, bool) {
+	if handler.channelAttribute == "" || len(handler.groups) != 0 || attr.Key != handler.channelAttribute {
+		return "", false
+	}
+
+	return attr.Value.Resolve().String(), true
+}
+
+func levelFromSlog(
+	level slog.Level,
+) Level {
+	switch {
+	case level >= slog.LevelError+4:
+		return Fatal
+	case level >= slog.LevelError:
+		return Error
+	case level >= slog.LevelWarn:
+		return Warning
+	case level > slog.LevelInfo:
+		return Notice
+	case level >= slog.LevelInfo:
+		return Info
+	default:
+		return Debug
+	}
+}
+
+func slogGroup(
+	bag flam.Bag,
+	groups []string,
+) flam.Bag {
+	for _, group := range groups {
+		next, ok := bag[group].(flam.Bag)
+		if !ok {
+			next = flam.Bag{}
+			bag[group] = next
+		}
+		bag = next
+	}
+
+	return bag
+}
+
+func slogMerge(
+	bag flam.Bag,
+	attrs flam.Bag,
+) {
+	for key, value := range attrs {
+		group, ok := value.(flam.Bag)
+		if target, exists := bag[key].(flam.Bag); ok && exists {
+			slogMerge(target, group)
+			continue
+		}
+		bag[key] = value
+	}
+}
+
+func slogAttr(
+	bag flam.Bag,
+	attr slog.Attr,
+) {
+	value := attr.Value.Resolve()
+	if attr.Key == "" && value.Kind() != slog.KindGroup {
+		return
+	}
+
+	switch value.Kind() {
+	case slog.KindGroup:
+		group := flam.Bag{}
+		for _, child := range value.Group() {
+			slogAttr(group, child)
+		}
+		if len(group) == 0 {
+			return
+		}
+
+		if attr.Key != "" {
+			bag = slogGroup(bag, []string{attr.Key})
+		}
+		slogMerge(bag, group)
+	case slog.KindAny:
+		if e, ok := value.Any().(error); ok {
+			bag[attr.Key] = e.Error()
+			return
+		}
+		bag[attr.Key] = value.Any()
+	default:
+		bag[attr.Key] = value.Any()
+	}
+}
@@ -0,0 +1,166 @@
+package log
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"testing/slogtest"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	flam "github.com/happyhippyhippo/flam"
+)
+
+func Test_slogHandler(t *testing.T) {
+	setup := func(t *testing.T, stream Stream) Facade {
+		container := newTestContainer(t, flam.Bag{})
+
+		var facade Facade
+		require.NoError(t, container.Invoke(func(f Facade) { facade = f }))
+		require.NoError(t, facade.AddStream("stream", stream))
+
+		return facade
+	}
+
+	t.Run("should map the slog levels", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		stream := NewStreamMock(ctrl)
+		for _, level := range []Level{Debug, Info, Notice, Warning, Error, Fatal} {
+			stream.EXPECT().Broadcast(gomock.Any(), level, "message", flam.Bag{}).Return(nil).Times(1)
+		}
+
+		facade := setup(t, stream)
+		logger := slog.New(NewSlogHandler(facade, "channel"))
+
+		for _, level := range []slog.Level{
+			slog.LevelDebug,
+			slog.LevelInfo,
+			slog.LevelInfo + 2,
+			slog.LevelWarn,
+			slog.LevelError,
+			slog.LevelError + 4,
+		} {
+			logger.Log(context.Background(), level, "message")
+		}
+
+		assert.NoError(t, facade.Flush())
+	})
+
+	t.Run("should convert the attributes and groups into nested bags", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		stream := NewStreamMock(ctrl)
+		stream.EXPECT().Broadcast(gomock.Any(), Info, "message", flam.Bag{
+			"service": "api",
+			"request": flam.Bag{
+				"id":     int64(12),
+				"user":   flam.Bag{"name": "john"},
+				"error":  "failure",
+				"inline": true,
+				"took":   time.Second,
+			},
+		}).Return(nil).Times(1)
+
+		facade := setup(t, stream)
+		logger := slog.New(NewSlogHandler(facade, "channel")).
+			With("service", "api").
+			WithGroup("request").
+			With("id", 12)
+
+		logger.Info(
+			"message",
+			slog.Group("user", "name", "john"),
+			slog.Group("", "inline", true),
+			slog.Group("empty"),
+			"error", errors.New("failure"),
+			"took", time.Second)
+
+		assert.NoError(t, facade.Flush())
+	})
+
+	t.Run("should select the channel from the configured attribute", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		stream := NewStreamMock(ctrl)
+		stream.EXPECT().Signal(gomock.Any(), Warning, "http", "first", flam.Bag{"id": int64(1)}).Return(nil).Times(1)
+		stream.EXPECT().Signal(gomock.Any(), Info, "db", "second", flam.Bag{}).Return(nil).Times(1)
+		stream.EXPECT().Broadcast(gomock.Any(), Info, "third", flam.Bag{"group": flam.Bag{"channel": "grouped"}}).Return(nil).Times(1)
+
+		facade := setup(t, stream)
+		logger := slog.New(NewSlogHandler(facade, "channel"))
+
+		logger.With("channel", "http").Warn("first", "id", 1)
+		logger.Info("second", "channel", "db")
+		logger.WithGroup("group").Info("third", "channel", "grouped")
+
+		assert.NoError(t, facade.Flush())
+	})
+
+	t.Run("should keep the slog record time", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		timestamp := time.Date(2021, 1, 2, 3, 4, 5, 6000000, time.UTC)
+
+		stream := NewStreamMock(ctrl)
+		stream.EXPECT().Broadcast(timestamp, Info, "message", flam.Bag{}).Return(nil).Times(1)
+
+		facade := setup(t, stream)
+		handler := NewSlogHandler(facade, "channel")
+
+		require.NoError(t, handler.Handle(context.Background(), slog.NewRecord(timestamp, slog.LevelInfo, "message", 0)))
+
+		assert.NoError(t, facade.Flush())
+	})
+	t.Run("should satisfy the slog handler contract", func(t *testing.T) {
+		var plain func(bag flam.Bag) map[string]any
+		plain = func(bag flam.Bag) map[string]any {
+			result := map[string]any{}
+			for key, value := range bag {
+				if group, ok := value.(flam.Bag); ok {
+					value = plain(group)
+				}
+				result[key] = value
+			}
+			return result
+		}
+
+		var facade Facade
+		var entry map[string]any
+		slogtest.Run(t, func(t *testing.T) slog.Handler {
+			if strings.HasSuffix(t.Name(), "/zero-time") {
+				t.Skip("the entries without a time are stamped when signaled")
+			}
+
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+
+			stream := NewStreamMock(ctrl)
+			stream.EXPECT().Broadcast(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+				DoAndReturn(func(timestamp time.Time, level Level, message string, ctx flam.Bag) error {
+					entry = plain(ctx)
+					entry[slog.TimeKey] = timestamp
+					entry[slog.LevelKey] = level
+					entry[slog.MessageKey] = message
+					return nil
+				}).
+				AnyTimes()
+
+			entry = nil
+			facade = setup(t, stream)
+			return NewSlogHandler(facade, "")
+		}, func(t *testing.T) map[string]any {
+			require.NoError(t, facade.Flush())
+			return entry
+		})
+	})
+}
@@ -0,0 +1,108 @@
+package log
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"sort"
+
+	flam "github.com/happyhippyhippo/flam"
+)
+
+type NamedSlogHandler struct {
+	Id      string
+	Handler slog.Handler
+}
+
+type slogWriter struct {
+	handler          slog.Handler
+	channelAttribute string
+}
+
+func NewSlogStream(
+	level Level,
+	channels []string,
+	handler slog.Handler,
+) Stream {
+	return newSlogStream(level, channels, handler, DefaultSlogChannelAttribute)
+}
+
+func newSlogStream(
+	level Level,
+	channels []string,
+	handler slog.Handler,
+	channelAttribute string,
+) Stream {
+	channels = slices.Clone(channels)
+	sort.Strings(channels)
+
+	return newEntryStream(
+		level,
+		channels,
+		&slogWriter{
+			handler:          handler,
+			channelAttribute: channelAttribute,
+		})
+}
+
+func (writer *slogWriter) writeEntry(
+	entry Entry,
+	_ []byte,
+) error {
+	ctx := context.Background()
+	level := levelToSlog(entry.Level)
+	if !writer.handler.Enabled(ctx, level) {
+		return nil
+	}
+
+	record := slog.NewRecord(entry.Timestamp, level, entry.Message, 0)
+	if entry.Channel != "" && writer.channelAttribute != "" {
+		record.AddAttrs(slog.String(writer.channelAttribute, entry.Channel))
+	}
+	record.AddAttrs(slogAttrs(entry.Ctx)...)
+
+	return writer.handler.Handle(ctx, record)
+}
+
+func levelToSlog(
+	level Level,
+) slog.Level {
+	switch level {
+	case Fatal:
+		return slog.LevelError + 4
+	case Error:
+		return slog.LevelError
+	case Warning:
+		return slog.LevelWarn
+	case Notice:
+		return slog.LevelInfo + 2
+	case Info:
+		return slog.LevelInfo
+	default:
+		return slog.LevelDebug
+	}
+}
+
+func slogAttrs(
+	bag flam.Bag,
+) []slog.Attr {
+	keys := make([]string, 0, len(bag))
+	for key := range bag {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	attrs := make([]slog.Attr, 0, len(keys))
+	for _, key := range keys {
+		switch value := bag[key].(type) {
+		case flam.Bag:
+			attrs = append(attrs, slog.Attr{Key: key, Value: slog.GroupValue(slogAttrs(value)...)})
+		case map[string]any:
+			attrs = append(attrs, slog.Attr{Key: key, Value: slog.GroupValue(slogAttrs(value)...)})
+		default:
+			attrs = append(attrs, slog.Any(key, value))
+		}
+	}
+
+	return attrs
+}
@@ -0,0 +1,56 @@
+package log
+
+import (
+	"log/slog"
+
+	"go.uber.org/dig"
+
+	flam "github.com/happyhippyhippo/flam"
+)
+
+type slogStreamCreatorArgs struct {
+	dig.In
+
+	Handlers []NamedSlogHandler `group:"flam.log.slog.handlers"`
+}
+
+type slogStreamCreator struct {
+	streamCreator
+	handlers map[string]slog.Handler
+}
+
+func newSlogStreamCreator(
+	args slogStreamCreatorArgs,
+) StreamCreator {
+	handlers := map[string]slog.Handler{}
+	for _, handler := range args.Handlers {
+		handlers[handler.Id] = handler.Handler
+	}
+
+	return &slogStreamCreator{
+		handlers: handlers,
+	}
+}
+
+func (slogStreamCreator) Accept(
+	config flam.Bag,
+) bool {
+	return config.String("driver") == StreamDriverSlog &&
+		config.Has("handler")
+}
+
+func (creator slogStreamCreator) Create(
+	config flam.Bag,
+) (Stream, error) {
+	id := config.String("handler")
+	handler, ok := creator.handlers[id]
+	if !ok || handler == nil {
+		return nil, newErrSlogHandlerNotFound(id)
+	}
+
+	return newSlogStream(
+		LevelFrom(config.Get("level"), DefaultLevel),
+		creator.getChannels(config.Slice("channels")),
+		handler,
+		config.String("channel_attribute", DefaultSlogChannelAttribute)), nil
+}
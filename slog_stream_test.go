@@ -0,0 +1,106 @@
+package log
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/dig"
+
+	flam "github.com/happyhippyhippo/flam"
+)
+
+func Test_slogStream(t *testing.T) {
+	timestamp := time.Date(2021, 1, 2, 3, 4, 5, 6000000, time.UTC)
+
+	t.Run("should forward the entries to the wrapped handler", func(t *testing.T) {
+		buffer := &bytes.Buffer{}
+		stream := NewSlogStream(Debug, []string{"http"}, slog.NewJSONHandler(buffer, &slog.HandlerOptions{
+			Level: slog.LevelDebug,
+		}))
+		defer func() { _ = stream.Close() }()
+
+		require.NoError(t, stream.Signal(timestamp, Error, "http", "message", flam.Bag{
+			"request": flam.Bag{"id": 12},
+			"user":    "john",
+		}))
+		require.NoError(t, stream.Signal(timestamp, Error, "db", "ignored", flam.Bag{}))
+
+		assert.Equal(
+			t,
+			`{"time":"2021-01-02T03:04:05.006Z","level":"ERROR","msg":"message",`+
+				`"channel":"http","request":{"id":12},"user":"john"}`+"\n",
+			buffer.String())
+	})
+
+	t.Run("should map the levels to the slog levels", func(t *testing.T) {
+		buffer := &bytes.Buffer{}
+		stream := NewSlogStream(Debug, nil, slog.NewTextHandler(buffer, &slog.HandlerOptions{
+			Level: slog.LevelDebug,
+			ReplaceAttr: func(_ []string, attr slog.Attr) slog.Attr {
+				if attr.Key == slog.TimeKey || attr.Key == slog.MessageKey {
+					return slog.Attr{}
+				}
+				return attr
+			},
+		}))
+
+		for _, level := range []Level{Fatal, Error, Warning, Notice, Info, Debug} {
+			require.NoError(t, stream.Broadcast(timestamp, level, "message", flam.Bag{}))
+		}
+
+		assert.Equal(
+			t,
+			"level=ERROR+4\nlevel=ERROR\nlevel=WARN\nlevel=INFO+2\nlevel=INFO\nlevel=DEBUG\n",
+			buffer.String())
+	})
+
+	t.Run("should skip the entries disabled by the wrapped handler", func(t *testing.T) {
+		buffer := &bytes.Buffer{}
+		stream := NewSlogStream(Debug, nil, slog.NewTextHandler(buffer, &slog.HandlerOptions{
+			Level: slog.LevelWarn,
+		}))
+
+		require.NoError(t, stream.Broadcast(timestamp, Info, "message", flam.Bag{}))
+
+		assert.Empty(t, buffer.String())
+	})
+}
+
+func Test_slogStreamCreator(t *testing.T) {
+	provideHandler := func(handler slog.Handler) func(container *dig.Container) error {
+		return func(container *dig.Container) error {
+			return container.Provide(func() NamedSlogHandler {
+				return NamedSlogHandler{Id: "json", Handler: handler}
+			}, dig.Group(SlogHandlerGroup))
+		}
+	}
+
+	timestamp := time.Date(2021, 1, 2, 3, 4, 5, 6000000, time.UTC)
+
+	t.Run("should return an error on an unknown handler", func(t *testing.T) {
+		_, e := getDriverTestStream(t, StreamDriverSlog, flam.Bag{"handler": "invalid"}, provideHandler(slog.NewJSONHandler(&bytes.Buffer{}, nil)))
+
+		assert.ErrorIs(t, e, ErrSlogHandlerNotFound)
+	})
+
+	t.Run("should create a stream backed by the named handler", func(t *testing.T) {
+		buffer := &bytes.Buffer{}
+		stream, e := getDriverTestStream(t, StreamDriverSlog, flam.Bag{
+			"handler":           "json",
+			"channels":          []any{"*"},
+			"channel_attribute": "logger",
+		}, provideHandler(slog.NewJSONHandler(buffer, nil)))
+		require.NoError(t, e)
+
+		require.NoError(t, stream.Signal(timestamp, Warning, "http", "message", flam.Bag{}))
+
+		assert.Equal(
+			t,
+			`{"time":"2021-01-02T03:04:05.006Z","level":"WARN","msg":"message","logger":"http"}`+"\n",
+			buffer.String())
+	})
+}
@@ -0,0 +1,77 @@
+package log
+
+import (
+	"sort"
+
+	flam "github.com/happyhippyhippo/flam"
+	filesystem "github.com/happyhippyhippo/flam-filesystem"
+)
+
+type socketStreamCreator struct {
+	streamCreator
+
+	fileSystemFacade filesystem.Facade
+}
+
+func newSocketStreamCreator(
+	fileSystemFacade filesystem.Facade,
+	serializerFactory serializerFactory,
+) StreamCreator {
+	return &socketStreamCreator{
+		streamCreator: streamCreator{
+			serializerFactory: serializerFactory,
+		},
+		fileSystemFacade: fileSystemFacade,
+	}
+}
+
+func (socketStreamCreator) Accept(
+	config flam.Bag,
+) bool {
+	return config.String("driver") == StreamDriverSocket &&
+		config.Has("address")
+}
+
+func (creator socketStreamCreator) Create(
+	config flam.Bag,
+) (Stream, error) {
+	serializerId := config.String("serializer", DefaultSerializer)
+	serializer, e := creator.serializerFactory.Get(serializerId)
+	if e != nil {
+		return nil, e
+	}
+
+	tlsConfig, e := tlsConfigFrom(config.Bag("tls"), creator.fileSystemFacade)
+	if e != nil {
+		return nil, e
+	}
+
+	reconnect := config.Bag("reconnect", flam.Bag{})
+	spill := config.Bag("spill", flam.Bag{})
+
+	writer := newSocketWriter(
+		newNetConnection(netConnectionOptions{
+			network: config.String("network", "tcp"),
+			address: config.String("address"),
+			timeout: config.Duration("timeout", DefaultNetTimeout),
+			tls:     tlsConfig,
+			retry: retryPolicy{
+				backoff:    reconnect.Duration("backoff", DefaultRetryBackoff),
+				maxBackoff: reconnect.Duration("max_backoff", DefaultRetryMaxBackoff),
+			},
+		}),
+		socketWriterOptions{
+			maxEntries: spill.Int("max_entries", DefaultSpillMaxEntries),
+			maxBytes:   spill.Int("max_bytes"),
+		})
+
+	channels := creator.getChannels(config.Slice("channels"))
+	sort.Strings(channels)
+
+	return newStream(
+		LevelFrom(config.Get("level"), DefaultLevel),
+		channels,
+		serializer,
+		writer,
+		true), nil
+}
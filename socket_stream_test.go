@@ -0,0 +1,208 @@
+package log
+
+import (
+	"bufio"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	flam "github.com/happyhippyhippo/flam"
+)
+
+func Test_socketStream(t *testing.T) {
+	accept := func(t *testing.T, listener net.Listener) chan string {
+		lines := make(chan string, 10)
+		go func() {
+			conn, e := listener.Accept()
+			if e != nil {
+				return
+			}
+			defer func() { _ = conn.Close() }()
+
+			scanner := bufio.NewScanner(conn)
+			for scanner.Scan() {
+				lines <- scanner.Text()
+			}
+		}()
+
+		return lines
+	}
+
+	receive := func(t *testing.T, lines chan string) string {
+		select {
+		case line := <-lines:
+			return line
+		case <-time.After(time.Second):
+			assert.Fail(t, "line not received")
+			return ""
+		}
+	}
+
+	timestamp := time.Date(2021, 1, 2, 3, 4, 5, 6000000, time.UTC)
+
+	t.Run("should write the serialized entries to the socket", func(t *testing.T) {
+		listener, e := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, e)
+		defer func() { _ = listener.Close() }()
+		lines := accept(t, listener)
+
+		stream, e := getDriverTestStream(t, StreamDriverSocket, flam.Bag{"address": listener.Addr().String()})
+		require.NoError(t, e)
+
+		require.NoError(t, stream.Broadcast(timestamp, Info, "message", flam.Bag{"key": "value"}))
+
+		assert.Equal(t, "time=2021-01-02T03:04:05.006+0000 level=info msg=message key=value", receive(t, lines))
+	})
+
+	t.Run("should write the entries over mutual tls with the configured files", func(t *testing.T) {
+		certFile, keyFile, certificate := writeTestCertificate(t)
+		pool := x509.NewCertPool()
+		pool.AddCert(certificate.Leaf)
+
+		listener, e := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+			Certificates: []tls.Certificate{certificate},
+			ClientCAs:    pool,
+			ClientAuth:   tls.RequireAndVerifyClientCert,
+		})
+		require.NoError(t, e)
+		defer func() { _ = listener.Close() }()
+		lines := accept(t, listener)
+
+		stream, e := getDriverTestStream(t, StreamDriverSocket, flam.Bag{
+			"address": listener.Addr().String(),
+			"tls": flam.Bag{
+				"enabled":     true,
+				"server_name": "localhost",
+				"disk":        "os",
+				"ca_file":     certFile,
+				"cert_file":   certFile,
+				"key_file":    keyFile,
+			},
+		})
+		require.NoError(t, e)
+
+		require.NoError(t, stream.Broadcast(timestamp, Info, "message", flam.Bag{}))
+
+		assert.Equal(t, "time=2021-01-02T03:04:05.006+0000 level=info msg=message", receive(t, lines))
+	})
+
+	t.Run("should return an error on invalid tls files", func(t *testing.T) {
+		_, e := getDriverTestStream(t, StreamDriverSocket, flam.Bag{
+			"address": "127.0.0.1:0",
+			"tls":     flam.Bag{"enabled": true, "disk": "os", "ca_file": filepath.Join(t.TempDir(), "missing.pem")},
+		})
+
+		assert.ErrorIs(t, e, ErrInvalidTlsConfig)
+	})
+
+	t.Run("should drop a partially written entry on a stalled peer", func(t *testing.T) {
+		listener, e := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, e)
+		defer func() { _ = listener.Close() }()
+
+		stream, e := getDriverTestStream(t, StreamDriverSocket, flam.Bag{
+			"address": listener.Addr().String(),
+			"timeout": 50 * time.Millisecond,
+		})
+		require.NoError(t, e)
+
+		message := strings.Repeat("x", 64*1024*1024)
+		assert.ErrorIs(t, stream.Broadcast(timestamp, Info, message, flam.Bag{}), ErrPartialWrite)
+
+		stalled, e := listener.Accept()
+		require.NoError(t, e)
+		_ = stalled.Close()
+		lines := accept(t, listener)
+
+		require.NoError(t, stream.Broadcast(timestamp, Info, "message", flam.Bag{}))
+
+		assert.Equal(t, "time=2021-01-02T03:04:05.006+0000 level=info msg=message", receive(t, lines))
+	})
+
+	t.Run("should spill the entries while the connection is down", func(t *testing.T) {
+		listener, e := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, e)
+		address := listener.Addr().String()
+		require.NoError(t, listener.Close())
+
+		stream, e := getDriverTestStream(t, StreamDriverSocket, flam.Bag{"address": address})
+		require.NoError(t, e)
+
+		require.NoError(t, stream.Broadcast(timestamp, Info, "message1", flam.Bag{}))
+		require.NoError(t, stream.Broadcast(timestamp, Info, "message2", flam.Bag{}))
+
+		listener, e = net.Listen("tcp", address)
+		require.NoError(t, e)
+		defer func() { _ = listener.Close() }()
+		lines := accept(t, listener)
+
+		time.Sleep(5 * time.Millisecond)
+		require.NoError(t, stream.Broadcast(timestamp, Info, "message3", flam.Bag{}))
+
+		assert.Contains(t, receive(t, lines), "msg=message1")
+		assert.Contains(t, receive(t, lines), "msg=message2")
+		assert.Contains(t, receive(t, lines), "msg=message3")
+	})
+
+	t.Run("should return an error when the spill buffer is full", func(t *testing.T) {
+		listener, e := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, e)
+		address := listener.Addr().String()
+		require.NoError(t, listener.Close())
+
+		stream, e := getDriverTestStream(t, StreamDriverSocket, flam.Bag{
+			"address": address,
+			"spill":   flam.Bag{"max_entries": 1},
+		})
+		require.NoError(t, e)
+
+		require.NoError(t, stream.Broadcast(timestamp, Info, "message1", flam.Bag{}))
+		assert.ErrorIs(t, stream.Broadcast(timestamp, Info, "message2", flam.Bag{}), ErrSpillBufferFull)
+	})
+}
+
+func writeTestCertificate(
+	t *testing.T,
+) (string, string, tls.Certificate) {
+	key, e := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, e)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		DNSNames:              []string{"localhost"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, e := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, e)
+	keyDer, e := x509.MarshalECPrivateKey(key)
+	require.NoError(t, e)
+
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0o600))
+
+	certificate, e := tls.LoadX509KeyPair(certFile, keyFile)
+	require.NoError(t, e)
+
+	return certFile, keyFile, certificate
+}
@@ -0,0 +1,90 @@
+package log
+
+import (
+	"sync"
+)
+
+type socketWriterOptions struct {
+	maxEntries int
+	maxBytes   int
+}
+
+type socketWriter struct {
+	lock       sync.Locker
+	connection *netConnection
+	options    socketWriterOptions
+	spill      [][]byte
+	size       int
+}
+
+func newSocketWriter(
+	connection *netConnection,
+	options socketWriterOptions,
+) *socketWriter {
+	return &socketWriter{
+		lock:       &sync.Mutex{},
+		connection: connection,
+		options:    options,
+	}
+}
+
+func (writer *socketWriter) Write(
+	output []byte,
+) (int, error) {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+
+	drained, e := writer.drain()
+	if drained {
+		if n, e := writer.connection.Write(output); e == nil || n != 0 {
+			return n, e
+		}
+	}
+
+	if writer.isFull(len(output)) {
+		return 0, newErrSpillBufferFull(writer.connection.options.address)
+	}
+
+	writer.spill = append(writer.spill, append([]byte(nil), output...))
+	writer.size += len(output)
+
+	return len(output), e
+}
+
+func (writer *socketWriter) Close() error {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+
+	_, e := writer.drain()
+	if ce := writer.connection.Close(); e == nil {
+		e = ce
+	}
+
+	return e
+}
+
+func (writer *socketWriter) drain() (bool, error) {
+	for len(writer.spill) != 0 {
+		n, e := writer.connection.Write(writer.spill[0])
+		if e != nil && n == 0 {
+			return false, nil
+		}
+
+		writer.size -= len(writer.spill[0])
+		writer.spill[0] = nil
+		writer.spill = writer.spill[1:]
+
+		if e != nil {
+			return false, e
+		}
+	}
+
+	return true, nil
+}
+
+func (writer *socketWriter) isFull(
+	size int,
+) bool {
+	return (writer.options.maxEntries > 0 && len(writer.spill) >= writer.options.maxEntries) ||
+		(writer.options.maxBytes > 0 && writer.size+size > writer.options.maxBytes)
+}
@@ -1,14 +1,43 @@
 package log
 
 import (
+	"bytes"
 	"io"
 	"slices"
 	"sort"
+	"sync"
 	"time"
 
 	flam "github.com/happyhippyhippo/flam"
 )
 
+const maxPooledSerializationBuffer = 64 * 1024
+
+var serializationBufferPool = sync.Pool{
+	New: func() any {
+		return &bytes.Buffer{}
+	},
+}
+
+func releaseSerializationBuffer(
+	buffer *bytes.Buffer,
+) {
+	if buffer.Cap() > maxPooledSerializationBuffer {
+		return
+	}
+
+	buffer.Reset()
+	serializationBufferPool.Put(buffer)
+}
+
+type entryWriter interface {
+	writeEntry(entry Entry, payload []byte) error
+}
+
+type reportingWriter interface {
+	report(reporter func(dropped int, e error))
+}
+
 type Stream interface {
 	Close() error
 
@@ -30,7 +59,9 @@ type stream struct {
 	channels   []string
 	serializer Serializer
 	writer     io.Writer
+	entries    entryWriter
 	doClose    bool
+	observer   func()
 }
 
 func newStream(
@@ -40,15 +71,30 @@ func newStream(
 	writer io.Writer,
 	doClose bool,
 ) *stream {
+	entries, _ := writer.(entryWriter)
+
 	return &stream{
 		level:      level,
 		channels:   channels,
 		serializer: serializer,
 		writer:     writer,
+		entries:    entries,
 		doClose:    doClose,
 	}
 }
 
+func newEntryStream(
+	level Level,
+	channels []string,
+	entries entryWriter,
+) *stream {
+	return &stream{
+		level:    level,
+		channels: channels,
+		entries:  entries,
+	}
+}
+
 func (stream *stream) Close() error {
 	if closer, ok := stream.writer.(io.Closer); stream.doClose && ok {
 		return closer.Close()
@@ -65,6 +111,7 @@ func (stream *stream) SetLevel(
 	level Level,
 ) error {
 	stream.level = level
+	stream.notify()
 
 	return nil
 }
@@ -85,6 +132,7 @@ func (stream *stream) AddChannel(
 	if !stream.HasChannel(channel) {
 		stream.channels = append(stream.channels, channel)
 		sort.Strings(stream.channels)
+		stream.notify()
 	}
 
 	return nil
@@ -96,12 +144,14 @@ func (stream *stream) RemoveChannel(
 	stream.channels = slices.DeleteFunc(stream.channels, func(c string) bool {
 		return c == channel
 	})
+	stream.notify()
 
 	return nil
 }
 
 func (stream *stream) RemoveAllChannels() error {
 	stream.channels = []string{}
+	stream.notify()
 
 	return nil
 }
@@ -117,9 +167,13 @@ func (stream *stream) Signal(
 		return nil
 	}
 
-	ctx["channel"] = channel
-
-	return stream.Broadcast(timestamp, level, message, ctx)
+	return stream.write(Entry{
+		Timestamp: timestamp,
+		Level:     level,
+		Channel:   channel,
+		Message:   message,
+		Ctx:       ctx,
+	})
 }
 
 func (stream *stream) Broadcast(
@@ -128,12 +182,34 @@ func (stream *stream) Broadcast(
 	message string,
 	ctx flam.Bag,
 ) error {
-	if stream.level < level || stream.level == None {
+	return stream.write(Entry{
+		Timestamp: timestamp,
+		Level:     level,
+		Message:   message,
+		Ctx:       ctx,
+	})
+}
+
+func (stream *stream) write(
+	entry Entry,
+) error {
+	if stream.level < entry.Level || stream.level == None {
 		return nil
 	}
 
-	serialized := stream.serializer.Serialize(timestamp, level, message, ctx)
-	_, e := stream.writer.Write([]byte(serialized))
+	buffer := serializationBufferPool.Get().(*bytes.Buffer)
+	defer releaseSerializationBuffer(buffer)
+
+	if stream.serializer == nil {
+		_, _ = buffer.WriteString(entry.Message)
+	} else if e := stream.serializer.Serialize(buffer, entry); e != nil {
+		return e
+	}
+
+	if stream.entries != nil {
+		return stream.entries.writeEntry(entry, buffer.Bytes())
+	}
+	_, e := stream.writer.Write(buffer.Bytes())
 
 	return e
 }
@@ -150,3 +226,23 @@ func (stream *stream) acceptChannel(
 
 	return i != len(stream.channels) && stream.channels[i] == channel
 }
+
+func (stream *stream) observe(
+	observer func(),
+) {
+	stream.observer = observer
+}
+
+func (stream *stream) report(
+	reporter func(dropped int, e error),
+) {
+	if writer, ok := stream.writer.(reportingWriter); ok {
+		writer.report(reporter)
+	}
+}
+
+func (stream *stream) notify() {
+	if stream.observer != nil {
+		stream.observer()
+	}
+}
@@ -0,0 +1,165 @@
+package log
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type streamWorker struct {
+	id        string
+	managed   *managedStream
+	policy    retryPolicy
+	queue     chan Entry
+	done      chan struct{}
+	mutex     sync.Locker
+	errs      []error
+	dropped   uint64
+	abandoned atomic.Bool
+	exited    bool
+	onExit    func()
+}
+
+func newStreamWorker(
+	id string,
+	managed *managedStream,
+	policy retryPolicy,
+	capacity int,
+) *streamWorker {
+	worker := &streamWorker{
+		id:      id,
+		managed: managed,
+		policy:  policy,
+		queue:   make(chan Entry, capacity),
+		done:    make(chan struct{}),
+		mutex:   &sync.Mutex{},
+	}
+
+	go worker.run()
+
+	return worker
+}
+
+func (worker *streamWorker) Enqueue(
+	entries []Entry,
+) int {
+	for i, entry := range entries {
+		select {
+		case worker.queue <- entry:
+		default:
+			return len(entries) - i
+		}
+	}
+
+	return 0
+}
+
+func (worker *streamWorker) Collect() (uint64, []error) {
+	worker.mutex.Lock()
+	defer worker.mutex.Unlock()
+
+	dropped, errs := worker.dropped, worker.errs
+	worker.dropped = 0
+	worker.errs = nil
+
+	return dropped, errs
+}
+
+func (worker *streamWorker) Stop() {
+	close(worker.queue)
+}
+
+func (worker *streamWorker) Drain(
+	timeout time.Duration,
+) bool {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-worker.done:
+		return true
+	case <-timer.C:
+		worker.abandoned.Store(true)
+		return false
+	}
+}
+
+func (worker *streamWorker) Handoff(
+	release func(),
+) {
+	worker.mutex.Lock()
+	if !worker.exited {
+		worker.onExit = release
+		worker.mutex.Unlock()
+		return
+	}
+	worker.mutex.Unlock()
+
+	release()
+}
+
+func (worker *streamWorker) run() {
+	defer worker.exit()
+
+	timer := time.NewTimer(time.Hour)
+	timer.Stop()
+
+	for {
+		var retry <-chan time.Time
+		if at, pending := worker.managed.RetryAt(); pending && !worker.abandoned.Load() {
+			timer.Reset(max(time.Until(at), minRetryInterval))
+			retry = timer.C
+		}
+
+		select {
+		case entry, ok := <-worker.queue:
+			timer.Stop()
+			if !ok {
+				return
+			}
+			if !worker.abandoned.Load() {
+				worker.deliver([]Entry{entry})
+			}
+		case <-retry:
+			worker.deliver(nil)
+		}
+	}
+}
+
+func (worker *streamWorker) deliver(
+	entries []Entry,
+) {
+	dropped, e := worker.managed.Deliver(entries, time.Now(), worker.policy)
+	if e != nil && !errors.Is(e, ErrStreamBackoff) {
+		e = newErrStreamDelivery(worker.id, e)
+	} else {
+		e = nil
+	}
+	worker.record(dropped, e)
+}
+
+func (worker *streamWorker) exit() {
+	worker.mutex.Lock()
+	worker.exited = true
+	release := worker.onExit
+	worker.mutex.Unlock()
+
+	close(worker.done)
+	if release != nil {
+		release()
+	}
+}
+
+func (worker *streamWorker) record(
+	dropped int,
+	e error,
+) {
+	worker.mutex.Lock()
+	defer worker.mutex.Unlock()
+
+	worker.dropped += uint64(dropped)
+	if e != nil {
+		worker.errs = append(worker.errs, e)
+	}
+}
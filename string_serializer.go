@@ -1,32 +1,200 @@
 package log
 
 import (
-	"fmt"
+	"bytes"
+	"encoding/json"
+	"io"
 	"strings"
 	"time"
 
 	flam "github.com/happyhippyhippo/flam"
 )
 
-type stringSerializer struct{}
+const (
+	defaultStringSerializerFormat     = "{time} [{level|upper}] {message}"
+	defaultStringSerializerTimeLayout = "2006-01-02T15:04:05.000-0700"
+)
+
+type stringSerializerOptions struct {
+	format     string
+	timeLayout string
+	location   *time.Location
+	context    string
+}
+
+type stringSegment struct {
+	literal string
+	field   string
+	filters []string
+}
 
-func newStringSerializer() Serializer {
-	return &stringSerializer{}
+type stringSerializer struct {
+	segments   []stringSegment
+	timeLayout string
+	location   *time.Location
+	context    string
+}
+
+func newStringSerializer(
+	options stringSerializerOptions,
+) (Serializer, error) {
+	if options.format == "" {
+		options.format = defaultStringSerializerFormat
+	}
+	if options.timeLayout == "" {
+		options.timeLayout = defaultStringSerializerTimeLayout
+	}
+
+	switch options.context {
+	case "":
+		options.context = StringContextLogfmt
+	case StringContextLogfmt, StringContextJson:
+	default:
+		return nil, newErrUnknownStringContextMode(options.context)
+	}
+
+	segments, e := parseStringFormat(options.format)
+	if e != nil {
+		return nil, e
+	}
+
+	return &stringSerializer{
+		segments:   segments,
+		timeLayout: options.timeLayout,
+		location:   options.location,
+		context:    options.context,
+	}, nil
+}
+
+func parseStringFormat(
+	format string,
+) ([]stringSegment, error) {
+	var segments []stringSegment
+	for format != "" {
+		start := strings.IndexByte(format, '{')
+		if start < 0 {
+			segments = append(segments, stringSegment{literal: format})
+			break
+		}
+
+		end := strings.IndexByte(format[start:], '}')
+		if end < 0 {
+			segments = append(segments, stringSegment{literal: format})
+			break
+		}
+		end += start
+
+		if start > 0 {
+			segments = append(segments, stringSegment{literal: format[:start]})
+		}
+
+		parts := strings.Split(format[start+1:end], "|")
+		if !isStringField(parts[0]) {
+			return nil, newErrUnknownStringPlaceholder(format[start : end+1])
+		}
+		for _, filter := range parts[1:] {
+			if filter != "upper" && filter != "lower" {
+				return nil, newErrInvalidSerializerFormat(format[start : end+1])
+			}
+		}
+		segments = append(segments, stringSegment{field: parts[0], filters: parts[1:]})
+		format = format[end+1:]
+	}
+
+	return segments, nil
+}
+
+func isStringField(
+	field string,
+) bool {
+	switch field {
+	case "time", "level", "message", "channel", "ctx":
+		return true
+	}
+
+	path, ok := strings.CutPrefix(field, "ctx.")
+
+	return ok && path != ""
 }
 
 func (stringSerializer) Close() error {
 	return nil
 }
 
-func (stringSerializer) Serialize(
-	timestamp time.Time,
-	level Level,
-	message string,
-	_ flam.Bag,
+func (serializer stringSerializer) Serialize(
+	writer io.Writer,
+	entry Entry,
+) error {
+	output := &serializationWriter{writer: writer}
+	for _, segment := range serializer.segments {
+		switch {
+		case segment.field == "":
+			_, _ = output.WriteString(segment.literal)
+		case segment.field == "ctx" && len(segment.filters) == 0:
+			serializer.writeContext(output, entry.Ctx)
+		default:
+			value := serializer.field(segment.field, entry)
+			for _, filter := range segment.filters {
+				switch filter {
+				case "upper":
+					value = strings.ToUpper(value)
+				case "lower":
+					value = strings.ToLower(value)
+				}
+			}
+			_, _ = output.WriteString(value)
+		}
+	}
+	_ = output.WriteByte('\n')
+
+	return output.e
+}
+
+func (serializer stringSerializer) field(
+	field string,
+	entry Entry,
 ) string {
-	return fmt.Sprintf(
-		"%s [%s] %s\n",
-		timestamp.Format("2006-01-02T15:04:05.000-0700"),
-		strings.ToUpper(LevelName[level]),
-		message)
+	switch field {
+	case "time":
+		timestamp := entry.Timestamp
+		if serializer.location != nil {
+			timestamp = timestamp.In(serializer.location)
+		}
+		return timestamp.Format(serializer.timeLayout)
+	case "level":
+		return LevelName[entry.Level]
+	case "message":
+		return entry.Message
+	case "channel":
+		return entry.Channel
+	case "ctx":
+		builder := &strings.Builder{}
+		serializer.writeContext(&serializationWriter{writer: builder}, entry.Ctx)
+		return builder.String()
+	}
+
+	if value := entry.Ctx.Get(strings.TrimPrefix(field, "ctx.")); value != nil {
+		return logfmtSerializer{}.format(value)
+	}
+
+	return ""
+}
+
+func (serializer stringSerializer) writeContext(
+	writer *serializationWriter,
+	ctx flam.Bag,
+) {
+	if len(ctx) == 0 {
+		return
+	}
+
+	if serializer.context == StringContextJson {
+		data, _ := json.Marshal(ctx)
+		_, _ = writer.Write(data)
+		return
+	}
+
+	buffer := &bytes.Buffer{}
+	logfmtSerializer{}.writeBag(&serializationWriter{writer: buffer}, ctx, nil)
+	_, _ = writer.Write(bytes.TrimPrefix(buffer.Bytes(), []byte{' '}))
 }
@@ -1,13 +1,22 @@
 package log
 
 import (
+	"time"
+
 	flam "github.com/happyhippyhippo/flam"
+	flamTime "github.com/happyhippyhippo/flam-time"
 )
 
-type stringSerializerCreator struct{}
+type stringSerializerCreator struct {
+	timeFacade flamTime.Facade
+}
 
-func newStringSerializerCreator() SerializerCreator {
-	return &stringSerializerCreator{}
+func newStringSerializerCreator(
+	timeFacade flamTime.Facade,
+) SerializerCreator {
+	return &stringSerializerCreator{
+		timeFacade: timeFacade,
+	}
 }
 
 func (stringSerializerCreator) Accept(
@@ -16,8 +25,21 @@ func (stringSerializerCreator) Accept(
 	return config.String("driver") == SerializerDriverString
 }
 
-func (stringSerializerCreator) Create(
-	_ flam.Bag,
+func (creator stringSerializerCreator) Create(
+	config flam.Bag,
 ) (Serializer, error) {
-	return newStringSerializer(), nil
+	var location *time.Location
+	if timezone := config.String("timezone"); timezone != "" {
+		var e error
+		if location, e = creator.timeFacade.LoadLocation(timezone); e != nil {
+			return nil, e
+		}
+	}
+
+	return newStringSerializer(stringSerializerOptions{
+		format:     config.String("format"),
+		timeLayout: config.String("time_layout"),
+		location:   location,
+		context:    config.String("context"),
+	})
 }
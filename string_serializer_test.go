@@ -0,0 +1,131 @@
+package log
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/dig"
+
+	flam "github.com/happyhippyhippo/flam"
+	config "github.com/happyhippyhippo/flam-config"
+	filesystem "github.com/happyhippyhippo/flam-filesystem"
+	flamTime "github.com/happyhippyhippo/flam-time"
+)
+
+func Test_stringSerializer(t *testing.T) {
+	getSerializer := func(t *testing.T, cfg flam.Bag) (Serializer, error) {
+		cfg["driver"] = SerializerDriverString
+
+		return getTestSerializer(t, "string", cfg)
+	}
+
+	timestamp := time.Date(2021, 1, 2, 3, 4, 5, 6000000, time.UTC)
+
+	t.Run("should use the default format when none is configured", func(t *testing.T) {
+		serializer, e := getSerializer(t, flam.Bag{})
+		require.NoError(t, e)
+
+		assert.Equal(
+			t,
+			"2021-01-02T03:04:05.006+0000 [INFO] message\n",
+			serialize(t, serializer, Entry{Timestamp: timestamp, Level: Info, Message: "message", Ctx: flam.Bag{"key": "value"}}))
+	})
+
+	t.Run("should render the configured format", func(t *testing.T) {
+		serializer, e := getSerializer(t, flam.Bag{
+			"format":      "{time} {level|upper} [{channel}] {message} {ctx}",
+			"time_layout": time.DateTime,
+		})
+		require.NoError(t, e)
+
+		assert.Equal(
+			t,
+			"2021-01-02 03:04:05 WARNING [http] message request.id=12 user=\"john doe\"\n",
+			serialize(t, serializer, Entry{Timestamp: timestamp, Level: Warning, Channel: "http", Message: "message", Ctx: flam.Bag{
+				"user":    "john doe",
+				"request": flam.Bag{"id": 12},
+			}}))
+	})
+
+	t.Run("should render the context as json", func(t *testing.T) {
+		serializer, e := getSerializer(t, flam.Bag{
+			"format":  "{level} {message} {ctx}",
+			"context": StringContextJson,
+		})
+		require.NoError(t, e)
+
+		assert.Equal(
+			t,
+			"error message {\"key\":\"value\"}\n",
+			serialize(t, serializer, Entry{Timestamp: timestamp, Level: Error, Message: "message", Ctx: flam.Bag{"key": "value"}}))
+	})
+
+	t.Run("should render a single context field", func(t *testing.T) {
+		serializer, e := getSerializer(t, flam.Bag{
+			"format": "{message} request={ctx.request.id|upper} missing={ctx.missing}",
+		})
+		require.NoError(t, e)
+
+		assert.Equal(
+			t,
+			"message request=ABC missing=\n",
+			serialize(t, serializer, Entry{Timestamp: timestamp, Level: Info, Message: "message", Ctx: flam.Bag{"request": flam.Bag{"id": "abc"}}}))
+	})
+
+	t.Run("should format the time in the configured timezone", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		config.Defaults = flam.Bag{}
+		_ = config.Defaults.Set(PathSerializers, flam.Bag{
+			"string": flam.Bag{
+				"driver":      SerializerDriverString,
+				"format":      "{time} {message}",
+				"time_layout": time.DateTime,
+				"timezone":    "custom",
+			}})
+		defer func() { config.Defaults = flam.Bag{} }()
+
+		container := dig.New()
+		timeFacade := NewTimeFacadeMock(ctrl)
+		timeFacade.EXPECT().LoadLocation("custom").Return(time.FixedZone("custom", 3600), nil).Times(1)
+		require.NoError(t, container.Provide(func() flamTime.Facade { return timeFacade }))
+		require.NoError(t, filesystem.NewProvider().Register(container))
+		require.NoError(t, config.NewProvider().Register(container))
+		require.NoError(t, NewProvider().Register(container))
+		require.NoError(t, config.NewProvider().(flam.BootableProvider).Boot(container))
+
+		assert.NoError(t, container.Invoke(func(facade Facade) {
+			serializer, e := facade.GetSerializer("string")
+			require.NoError(t, e)
+
+			assert.Equal(
+				t,
+				"2021-01-02 04:04:05 message\n",
+				serialize(t, serializer, Entry{Timestamp: timestamp, Level: Info, Message: "message", Ctx: flam.Bag{}}))
+		}))
+	})
+
+	t.Run("should return an error on unknown filter", func(t *testing.T) {
+		_, e := getSerializer(t, flam.Bag{"format": "{level|invalid}"})
+
+		assert.ErrorIs(t, e, ErrInvalidSerializerFormat)
+	})
+
+	t.Run("should return an error on unknown placeholder", func(t *testing.T) {
+		for _, format := range []string{"{tpyo}", "{message} {ctx.}"} {
+			_, e := getSerializer(t, flam.Bag{"format": format})
+
+			assert.ErrorIs(t, e, ErrUnknownStringPlaceholder, format)
+		}
+	})
+
+	t.Run("should return an error on unknown context rendering", func(t *testing.T) {
+		_, e := getSerializer(t, flam.Bag{"context": "invalid"})
+
+		assert.ErrorIs(t, e, ErrUnknownStringContextMode)
+	})
+}
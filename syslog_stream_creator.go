@@ -0,0 +1,77 @@
+package log
+
+import (
+	"sort"
+
+	flam "github.com/happyhippyhippo/flam"
+	filesystem "github.com/happyhippyhippo/flam-filesystem"
+)
+
+type syslogStreamCreator struct {
+	streamCreator
+
+	fileSystemFacade filesystem.Facade
+}
+
+func newSyslogStreamCreator(
+	fileSystemFacade filesystem.Facade,
+	serializerFactory serializerFactory,
+) StreamCreator {
+	return &syslogStreamCreator{
+		streamCreator: streamCreator{
+			serializerFactory: serializerFactory,
+		},
+		fileSystemFacade: fileSystemFacade,
+	}
+}
+
+func (syslogStreamCreator) Accept(
+	config flam.Bag,
+) bool {
+	return config.String("driver") == StreamDriverSyslog
+}
+
+func (creator syslogStreamCreator) Create(
+	config flam.Bag,
+) (Stream, error) {
+	var serializer Serializer
+	if serializerId := config.String("serializer", DefaultSerializer); serializerId != "" {
+		var e error
+		if serializer, e = creator.serializerFactory.Get(serializerId); e != nil {
+			return nil, e
+		}
+	}
+
+	tlsConfig, e := tlsConfigFrom(config.Bag("tls"), creator.fileSystemFacade)
+	if e != nil {
+		return nil, e
+	}
+
+	writer, e := newSyslogWriter(
+		newNetConnection(netConnectionOptions{
+			network: config.String("network", "unixgram"),
+			address: config.String("address", "/dev/log"),
+			timeout: config.Duration("timeout", DefaultNetTimeout),
+			tls:     tlsConfig,
+		}),
+		syslogWriterOptions{
+			facility: config.String("facility"),
+			appName:  config.String("app_name"),
+			hostname: config.String("hostname"),
+			format:   config.String("format"),
+			sdId:     config.String("sd_id"),
+		})
+	if e != nil {
+		return nil, e
+	}
+
+	channels := creator.getChannels(config.Slice("channels"))
+	sort.Strings(channels)
+
+	return newStream(
+		LevelFrom(config.Get("level"), DefaultLevel),
+		channels,
+		serializer,
+		writer,
+		true), nil
+}
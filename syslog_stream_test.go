@@ -0,0 +1,158 @@
+package log
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	flam "github.com/happyhippyhippo/flam"
+)
+
+func Test_syslogStream(t *testing.T) {
+	timestamp := time.Date(2021, 1, 2, 3, 4, 5, 6000000, time.UTC)
+
+	t.Run("should return an error on unknown facility", func(t *testing.T) {
+		_, e := getDriverTestStream(t, StreamDriverSyslog, flam.Bag{"facility": "invalid"})
+
+		assert.ErrorIs(t, e, ErrUnknownSyslogFacility)
+	})
+
+	t.Run("should return an error on unknown format", func(t *testing.T) {
+		_, e := getDriverTestStream(t, StreamDriverSyslog, flam.Bag{"format": "invalid"})
+
+		assert.ErrorIs(t, e, ErrUnknownSyslogFormat)
+	})
+
+	t.Run("should send a rfc 5424 message with structured data over udp", func(t *testing.T) {
+		listener, read := listenTestUdp(t)
+
+		stream, e := getDriverTestStream(t, StreamDriverSyslog, flam.Bag{
+			"network":  "udp",
+			"address":  listener.LocalAddr().String(),
+			"facility": "local0",
+		})
+		require.NoError(t, e)
+		defer func() { _ = stream.Close() }()
+
+		require.NoError(t, stream.Signal(timestamp, Error, "http", "message", flam.Bag{
+			"user":    `john "doe"`,
+			"request": flam.Bag{"id": 12},
+		}))
+
+		assert.Regexp(
+			t,
+			`^<131>1 2021-01-02T03:04:05\.006000Z host app \d+ http `+
+				`\[ctx@32473 request\.id="12" user="john \\"doe\\""\] message$`,
+			string(read()))
+	})
+
+	t.Run("should send a rfc 3164 message through the serializer", func(t *testing.T) {
+		listener, read := listenTestUdp(t)
+
+		stream, e := getDriverTestStream(t, StreamDriverSyslog, flam.Bag{
+			"network":    "udp",
+			"address":    listener.LocalAddr().String(),
+			"format":     SyslogFormatRFC3164,
+			"serializer": "logfmt",
+		})
+		require.NoError(t, e)
+		defer func() { _ = stream.Close() }()
+
+		require.NoError(t, stream.Broadcast(timestamp, Warning, "message", flam.Bag{}))
+
+		assert.Regexp(
+			t,
+			`^<12>Jan  2 03:04:05 host app\[\d+\]: time=\S+ level=warning msg=message$`,
+			string(read()))
+	})
+
+	t.Run("should map the levels to the syslog severities", func(t *testing.T) {
+		listener, read := listenTestUdp(t)
+
+		stream, e := getDriverTestStream(t, StreamDriverSyslog, flam.Bag{
+			"network": "udp",
+			"address": listener.LocalAddr().String(),
+			"level":   "debug",
+		})
+		require.NoError(t, e)
+		defer func() { _ = stream.Close() }()
+
+		for level, priority := range map[Level]string{
+			Fatal:   "<10>",
+			Error:   "<11>",
+			Warning: "<12>",
+			Notice:  "<13>",
+			Info:    "<14>",
+			Debug:   "<15>",
+		} {
+			require.NoError(t, stream.Broadcast(timestamp, level, "message", flam.Bag{}))
+			assert.Regexp(t, "^"+priority+"1 ", string(read()))
+		}
+	})
+
+	t.Run("should frame the messages with the octet count over tcp", func(t *testing.T) {
+		listener, e := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, e)
+		defer func() { _ = listener.Close() }()
+
+		received := make(chan string, 1)
+		go func() {
+			conn, e := listener.Accept()
+			if e != nil {
+				return
+			}
+			defer func() { _ = conn.Close() }()
+
+			var length int
+			reader := bufio.NewReader(conn)
+			prefix, _ := reader.ReadString(' ')
+			_, _ = fmt.Sscan(prefix, &length)
+			buffer := make([]byte, length)
+			_, _ = io.ReadFull(reader, buffer)
+			received <- prefix + string(buffer)
+		}()
+
+		stream, e := getDriverTestStream(t, StreamDriverSyslog, flam.Bag{
+			"network": "tcp",
+			"address": listener.Addr().String(),
+		})
+		require.NoError(t, e)
+		defer func() { _ = stream.Close() }()
+
+		require.NoError(t, stream.Broadcast(timestamp, Info, "message", flam.Bag{}))
+
+		select {
+		case message := <-received:
+			assert.Regexp(t, `^\d+ <14>1 2021-01-02T03:04:05\.006000Z host app \d+ - - message$`, message)
+		case <-time.After(time.Second):
+			assert.Fail(t, "message not received")
+		}
+	})
+
+	t.Run("should send to a local unix datagram socket", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "log.sock")
+		listener, e := net.ListenPacket("unixgram", path)
+		require.NoError(t, e)
+		defer func() { _ = listener.Close() }()
+
+		stream, e := getDriverTestStream(t, StreamDriverSyslog, flam.Bag{"address": path})
+		require.NoError(t, e)
+		defer func() { _ = stream.Close() }()
+
+		require.NoError(t, stream.Signal(timestamp, Info, "channel", "message", flam.Bag{}))
+
+		buffer := make([]byte, 4096)
+		_ = listener.SetReadDeadline(time.Now().Add(time.Second))
+		n, _, e := listener.ReadFrom(buffer)
+		require.NoError(t, e)
+
+		assert.Regexp(t, `^<14>1 \S+ host app \d+ channel - message$`, string(buffer[:n]))
+	})
+}
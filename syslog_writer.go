@@ -0,0 +1,269 @@
+package log
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var syslogFacilities = map[string]int{
+	"kern":     0,
+	"user":     1,
+	"mail":     2,
+	"daemon":   3,
+	"auth":     4,
+	"syslog":   5,
+	"lpr":      6,
+	"news":     7,
+	"uucp":     8,
+	"cron":     9,
+	"authpriv": 10,
+	"ftp":      11,
+	"local0":   16,
+	"local1":   17,
+	"local2":   18,
+	"local3":   19,
+	"local4":   20,
+	"local5":   21,
+	"local6":   22,
+	"local7":   23,
+}
+
+var syslogSeverities = map[Level]int{
+	Fatal:   2,
+	Error:   3,
+	Warning: 4,
+	Notice:  5,
+	Info:    6,
+	Debug:   7,
+}
+
+type syslogWriterOptions struct {
+	facility string
+	appName  string
+	hostname string
+	format   string
+	sdId     string
+}
+
+type syslogWriter struct {
+	lock       sync.Locker
+	connection *netConnection
+	options    syslogWriterOptions
+	facility   int
+	pid        int
+}
+
+func newSyslogWriter(
+	connection *netConnection,
+	options syslogWriterOptions,
+) (*syslogWriter, error) {
+	writer := &syslogWriter{
+		lock:       &sync.Mutex{},
+		connection: connection,
+		options:    options,
+		pid:        os.Getpid(),
+	}
+
+	if writer.options.facility == "" {
+		writer.options.facility = "user"
+	}
+	facility, ok := syslogFacilities[writer.options.facility]
+	if !ok {
+		return nil, newErrUnknownSyslogFacility(writer.options.facility)
+	}
+	writer.facility = facility
+
+	switch writer.options.format {
+	case "":
+		writer.options.format = SyslogFormatRFC5424
+	case SyslogFormatRFC5424, SyslogFormatRFC3164:
+	default:
+		return nil, newErrUnknownSyslogFormat(writer.options.format)
+	}
+
+	if writer.options.appName == "" {
+		writer.options.appName = filepath.Base(os.Args[0])
+	}
+	if writer.options.hostname == "" {
+		writer.options.hostname, _ = os.Hostname()
+	}
+	if writer.options.sdId == "" {
+		writer.options.sdId = "ctx@32473"
+	}
+
+	return writer, nil
+}
+
+func (writer *syslogWriter) Write(
+	output []byte,
+) (int, error) {
+	e := writer.writeEntry(Entry{
+		Timestamp: time.Now(),
+		Level:     Info,
+		Message:   string(output),
+	}, output)
+	if e != nil {
+		return 0, e
+	}
+
+	return len(output), nil
+}
+
+func (writer *syslogWriter) Close() error {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+
+	return writer.connection.Close()
+}
+
+func (writer *syslogWriter) writeEntry(
+	entry Entry,
+	payload []byte,
+) error {
+	message := &bytes.Buffer{}
+	if writer.options.format == SyslogFormatRFC3164 {
+		writer.rfc3164(message, entry, payload)
+	} else {
+		writer.rfc5424(message, entry, payload)
+	}
+
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+
+	if writer.connection.IsStream() {
+		if writer.options.format == SyslogFormatRFC3164 {
+			_ = message.WriteByte('\n')
+		} else {
+			framed := strconv.Itoa(message.Len()) + " " + message.String()
+			message.Reset()
+			_, _ = message.WriteString(framed)
+		}
+	}
+
+	_, e := writer.connection.Write(message.Bytes())
+
+	return e
+}
+
+func (writer *syslogWriter) priority(
+	level Level,
+) string {
+	severity, ok := syslogSeverities[level]
+	if !ok {
+		severity = syslogSeverities[Debug]
+	}
+
+	return "<" + strconv.Itoa(writer.facility*8+severity) + ">"
+}
+
+func (writer *syslogWriter) rfc5424(
+	message *bytes.Buffer,
+	entry Entry,
+	payload []byte,
+) {
+	_, _ = message.WriteString(writer.priority(entry.Level))
+	_, _ = message.WriteString("1 ")
+	_, _ = message.WriteString(entry.Timestamp.Format("2006-01-02T15:04:05.000000Z07:00"))
+	_ = message.WriteByte(' ')
+	_, _ = message.WriteString(writer.header(writer.options.hostname, 255))
+	_ = message.WriteByte(' ')
+	_, _ = message.WriteString(writer.header(writer.options.appName, 48))
+	_ = message.WriteByte(' ')
+	_, _ = message.WriteString(strconv.Itoa(writer.pid))
+	_ = message.WriteByte(' ')
+	_, _ = message.WriteString(writer.header(entry.Channel, 32))
+	_ = message.WriteByte(' ')
+	writer.structuredData(message, entry)
+
+	if payload = bytes.TrimRight(payload, "\n"); len(payload) != 0 {
+		_ = message.WriteByte(' ')
+		_, _ = message.Write(payload)
+	}
+}
+
+func (writer *syslogWriter) rfc3164(
+	message *bytes.Buffer,
+	entry Entry,
+	payload []byte,
+) {
+	_, _ = message.WriteString(writer.priority(entry.Level))
+	_, _ = message.WriteString(entry.Timestamp.Format(time.Stamp))
+	_ = message.WriteByte(' ')
+	_, _ = message.WriteString(writer.header(writer.options.hostname, 255))
+	_ = message.WriteByte(' ')
+	_, _ = message.WriteString(writer.header(writer.options.appName, 32))
+	_, _ = message.WriteString("[" + strconv.Itoa(writer.pid) + "]: ")
+	_, _ = message.Write(bytes.TrimRight(payload, "\n"))
+}
+
+func (writer *syslogWriter) structuredData(
+	message *bytes.Buffer,
+	entry Entry,
+) {
+	fields := flattenContext("", entry.Ctx)
+	if len(fields) == 0 {
+		_ = message.WriteByte('-')
+		return
+	}
+
+	_ = message.WriteByte('[')
+	_, _ = message.WriteString(writer.options.sdId)
+	for _, field := range fields {
+		_ = message.WriteByte(' ')
+		_, _ = message.WriteString(writer.paramName(field.key))
+		_, _ = message.WriteString(`="`)
+		_, _ = message.WriteString(writer.paramValue(logfmtSerializer{}.format(field.value)))
+		_ = message.WriteByte('"')
+	}
+	_ = message.WriteByte(']')
+}
+
+func (syslogWriter) header(
+	value string,
+	limit int,
+) string {
+	if value == "" {
+		return "-"
+	}
+
+	value = strings.Map(func(r rune) rune {
+		if r <= ' ' || r > '~' {
+			return '_'
+		}
+		return r
+	}, value)
+
+	if len(value) > limit {
+		value = value[:limit]
+	}
+
+	return value
+}
+
+func (syslogWriter) paramName(
+	name string,
+) string {
+	name = strings.Map(func(r rune) rune {
+		if r <= ' ' || r > '~' || r == '=' || r == ']' || r == '"' {
+			return '_'
+		}
+		return r
+	}, name)
+
+	if len(name) > 32 {
+		name = name[:32]
+	}
+
+	return name
+}
+
+func (syslogWriter) paramValue(
+	value string,
+) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`).Replace(value)
+}